@@ -6,11 +6,16 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+	"github.com/tsocial/ui-version-mapping/pkg/condition"
 	"github.com/tsocial/ui-version-mapping/pkg/config"
+	"github.com/tsocial/ui-version-mapping/pkg/diagram"
+	"github.com/tsocial/ui-version-mapping/pkg/export"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+	"github.com/tsocial/ui-version-mapping/pkg/lint"
 )
 
 const (
@@ -25,7 +30,25 @@ func main() {
 		leadSource = flag.String("lead-source", "organic", "Lead source (organic, paid, etc.)")
 		configPath = flag.String("config-path", DefaultConfigPath, "Path to lender configs directory")
 		outputPath = flag.String("output", DefaultOutputPath, "Output directory for results")
-		mode       = flag.String("mode", "complete", "Analysis mode: complete, ab-testing, journey")
+		mode       = flag.String("mode", "complete", "Analysis mode: complete, ab-testing, ab-health, traffic, journey, mapping, simulate, route, diff, history, blame, lint, topology")
+		attrs      = flag.String("attrs", "", "User attributes for simulate/route mode, e.g. telco_code=viettel,communication_call=success")
+		abGrouping = flag.String("ab-grouping", analyzer.DefaultGroupingSpec, "A/B grouping strategy: name, tags, experiment; combine with + (all) and , (any)")
+		abTotal    = flag.Int("ab-weight-total", analyzer.DefaultABWeightTotal, "Total weight the variants of an A/B group must add up to (ab-health mode); 0 treats weights as relative shares")
+		users      = flag.Int("users", analyzer.DefaultSimulatedUsers, "Number of synthetic users (traffic mode)")
+		seed       = flag.String("seed", "", "Seed of the user hash (traffic mode)")
+		weights    = flag.String("weights", "", "Proposed weights to preview in traffic mode, e.g. 9012=60,9013=40")
+		gitRepo    = flag.String("git-repo", config.DigitalJourneyRepoPath, "digital_journey git repository read by -git-ref and git: snapshots")
+		gitRef     = flag.String("git-ref", "", "Read configs from this git ref (branch, tag, commit) of -git-repo instead of the working tree")
+		diffBase   = flag.String("diff-base", "", "Old snapshot in diff mode: a config root or git:<ref>")
+		diffHead   = flag.String("diff-head", "", "New snapshot in diff mode: a config root or git:<ref> (default: current configs)")
+		stepTpl    = flag.String("step-templates", "", "Step template file (JSON) overriding the built-in journey steps")
+		stepCat    = flag.String("step-catalogue", "", "Step catalogue file (JSON) overriding the built-in known ui_flow steps")
+		lintPolicy = flag.String("lint-policy", "", "Lint policy file (JSON) disabling rules globally or per folder")
+		lintOff    = flag.String("lint-disable", "", "Comma-separated lint rule IDs to disable, e.g. unknown-step,weight-range")
+		clusterBy  = flag.String("cluster-by", analyzer.ClusterByLeadSource, "Tag clustering the configs of the topology diagram: lead_source, product_code or empty for none")
+		diagrams   = flag.String("diagram-format", string(diagram.FormatPlantUML), "Comma-separated diagram formats: plantuml, mermaid")
+		renderer   = flag.String("image-renderer", string(diagram.ImageBackendAuto), "PNG renderer: auto (PlantUML when Java and plantuml.jar are available, else built-in), plantuml, builtin")
+		loadMode   = flag.String("load-mode", string(config.LoadLenient), "Handling of unreadable or malformed config files and duplicate config IDs: lenient (warn) or strict (fail)")
 		help       = flag.Bool("help", false, "Show help message")
 	)
 
@@ -58,31 +81,149 @@ func main() {
 	fmt.Printf("Output Path: %s\n", *outputPath)
 	fmt.Printf("Mode: %s\n\n", *mode)
 
-	// Create config provider - always use local
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// Create config provider - local files unless a git ref or diff head is requested
+	var providerSpec string
+	if *gitRef != "" {
+		providerSpec = "git:" + *gitRef
+	}
+	if *mode == "diff" && *diffHead != "" {
+		providerSpec = *diffHead
+	}
+	if (*mode == "history" || *mode == "blame") && providerSpec == "" {
+		providerSpec = "git:HEAD"
+	}
+
 	provider := config.GetConfigProvider()
-	fmt.Printf("Using local config provider\n")
+	providerLabel := providerSpec
+	if providerSpec != "" {
+		p, err := newConfigProvider(ctx, providerSpec, *gitRepo)
+		if err != nil {
+			log.Fatalf("Failed to create config provider: %v", err)
+		}
+		provider = p
+	} else if cached, ok := provider.(*config.CachedConfigProvider); ok {
+		providerLabel = cached.BasePath
+	}
+	fmt.Printf("Using config provider %s\n", providerLabel)
 
 	// Create analyzer service
 	analyzerService := analyzer.NewAnalyzerService(provider)
-
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
+	if *stepTpl != "" {
+		templates, err := journey.LoadStepTemplates(*stepTpl)
+		if err != nil {
+			log.Fatalf("Failed to load step templates: %v", err)
+		}
+		analyzerService.SetStepTemplates(templates)
+		fmt.Printf("Using step templates from %s\n", *stepTpl)
+	}
+	var catalogue *journey.StepCatalogue
+	if *stepCat != "" {
+		c, err := journey.LoadStepCatalogue(*stepCat)
+		if err != nil {
+			log.Fatalf("Failed to load step catalogue: %v", err)
+		}
+		catalogue = c
+		analyzerService.SetStepCatalogue(catalogue)
+		fmt.Printf("Using step catalogue from %s\n", *stepCat)
+	}
+	grouping, err := analyzer.ParseGroupingStrategy(*abGrouping)
+	if err != nil {
+		log.Fatalf("Invalid A/B grouping strategy: %v", err)
+	}
+	analyzerService.SetGroupingStrategy(grouping)
+	loading, err := config.ParseLoadMode(*loadMode)
+	if err != nil {
+		log.Fatalf("Invalid load mode: %v", err)
+	}
+	analyzerService.SetLoadMode(loading)
+	exporter := export.NewExporter(analyzerService, *outputPath)
+	formats, err := diagram.ParseFormats(*diagrams)
+	if err != nil {
+		log.Fatalf("Invalid diagram format: %v", err)
+	}
+	exporter.SetDiagramFormats(formats)
+	backend, err := diagram.ParseImageBackend(*renderer)
+	if err != nil {
+		log.Fatalf("Invalid image renderer: %v", err)
+	}
+	exporter.SetImageBackend(backend)
 
 	// Run analysis based on mode
 	switch *mode {
 	case "ab-testing":
-		err := runABTestingAnalysis(ctx, analyzerService, *configID, *leadSource, *configPath, *outputPath)
+		err := runABTestingAnalysis(ctx, exporter, *configID, *leadSource, *configPath)
 		if err != nil {
 			log.Fatalf("A/B testing analysis failed: %v", err)
 		}
+	case "ab-health":
+		healthy, err := runABHealth(ctx, exporter, *configPath, *abTotal)
+		if err != nil {
+			log.Fatalf("A/B health check failed: %v", err)
+		}
+		if !healthy {
+			fmt.Printf("\n❌ A/B health check found errors\n")
+			os.Exit(1)
+		}
+	case "lint":
+		clean, err := runLint(ctx, exporter, *configPath, *lintPolicy, *lintOff, catalogue)
+		if err != nil {
+			log.Fatalf("Lint failed: %v", err)
+		}
+		if !clean {
+			fmt.Printf("\n❌ Lint found errors\n")
+			os.Exit(1)
+		}
+	case "topology":
+		err := runTopology(ctx, exporter, *configPath, *clusterBy)
+		if err != nil {
+			log.Fatalf("Config topology failed: %v", err)
+		}
+	case "traffic":
+		err := runTrafficSplit(ctx, exporter, *configID, *configPath, *users, *seed, *weights)
+		if err != nil {
+			log.Fatalf("Traffic split simulation failed: %v", err)
+		}
+	case "diff":
+		err := runConfigDiff(ctx, exporter, *configPath, *diffBase, providerLabel, *gitRepo)
+		if err != nil {
+			log.Fatalf("Config diff failed: %v", err)
+		}
+	case "history":
+		err := runConfigHistory(ctx, exporter, *configID, *leadSource)
+		if err != nil {
+			log.Fatalf("Config history failed: %v", err)
+		}
+	case "blame":
+		err := runConfigBlame(ctx, exporter, *configID, *leadSource)
+		if err != nil {
+			log.Fatalf("Config blame failed: %v", err)
+		}
 	case "journey":
-		err := runJourneyAnalysis(ctx, analyzerService, *configID, *leadSource, *configPath, *outputPath)
+		err := runJourneyAnalysis(ctx, exporter, *configID, *leadSource, *configPath)
 		if err != nil {
 			log.Fatalf("Journey analysis failed: %v", err)
 		}
+	case "mapping":
+		err := runUIVersionMapping(ctx, exporter, *configID, *leadSource, *configPath)
+		if err != nil {
+			log.Fatalf("UI version mapping failed: %v", err)
+		}
+	case "simulate":
+		err := runSimulation(ctx, exporter, *configID, *leadSource, *configPath, *attrs)
+		if err != nil {
+			log.Fatalf("Journey simulation failed: %v", err)
+		}
+	case "route":
+		err := runRouting(ctx, analyzerService, *leadSource, *configPath, *attrs)
+		if err != nil {
+			log.Fatalf("Routing resolution failed: %v", err)
+		}
 	case "complete":
-		err := runCompleteAnalysis(ctx, analyzerService, *configID, *leadSource, *configPath, *outputPath)
+		err := runCompleteAnalysis(ctx, exporter, *configID, *leadSource, *configPath)
 		if err != nil {
 			log.Fatalf("Complete analysis failed: %v", err)
 		}
@@ -93,59 +234,308 @@ func main() {
 	fmt.Printf("\n🎉 Analysis completed successfully!\n")
 }
 
-func runABTestingAnalysis(ctx context.Context, service *analyzer.AnalyzerService, configID int, leadSource, configPath, outputPath string) error {
+func runABTestingAnalysis(ctx context.Context, exporter *export.Exporter, configID int, leadSource, configPath string) error {
 	fmt.Printf("=== Running A/B Testing Analysis ===\n")
 
-	// Find A/B testing groups
-	groups, err := service.FindABTestingGroups(ctx, configPath)
+	result, err := exporter.ExportABTestingAnalysis(ctx, configID, leadSource, configPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Found %d A/B testing groups\n", len(result.ABTestingGroups))
+	return nil
+}
+
+// runABHealth prints the A/B health findings and reports whether the check passed
+func runABHealth(ctx context.Context, exporter *export.Exporter, configPath string, expectedTotal int) (bool, error) {
+	fmt.Printf("=== Running A/B Health Check ===\n")
+
+	report, err := exporter.ExportABHealth(ctx, configPath, expectedTotal)
+	if err != nil {
+		return false, err
+	}
+
+	if report.ExpectedTotal > 0 {
+		fmt.Printf("Checked %d A/B groups (strategy %s, expected total %d)\n", report.Groups, report.Strategy, report.ExpectedTotal)
+	} else {
+		fmt.Printf("Checked %d A/B groups (strategy %s, weights as relative shares)\n", report.Groups, report.Strategy)
+	}
+	for _, finding := range report.Findings {
+		fmt.Printf("  [%s] %s %s: %s\n", finding.Severity, finding.Code, finding.Group, finding.Message)
+	}
+
+	return !report.HasErrors(), nil
+}
+
+// runLint prints the lint findings and reports whether the configs are free of errors.
+// A nil catalogue keeps the built-in step catalogue.
+func runLint(ctx context.Context, exporter *export.Exporter, configPath, policyFile, disable string, catalogue *journey.StepCatalogue) (bool, error) {
+	fmt.Printf("=== Running Config Lint ===\n")
+
+	var policy lint.Policy
+	if policyFile != "" {
+		p, err := lint.LoadPolicy(policyFile)
+		if err != nil {
+			return false, err
+		}
+		policy = p
+	}
+	for _, id := range strings.Split(disable, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			policy.Disable = append(policy.Disable, id)
+		}
+	}
+	if err := policy.Validate(lint.Catalogue()); err != nil {
+		return false, fmt.Errorf("invalid -lint-disable: %w", err)
+	}
+
+	linter := lint.NewLinter(policy)
+	if catalogue != nil {
+		linter.Options.Steps = catalogue
+	}
+
+	report, err := exporter.ExportLint(ctx, configPath, linter)
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Print(report.String())
+	return !report.HasErrors(), nil
+}
+
+func runTopology(ctx context.Context, exporter *export.Exporter, configPath, clusterBy string) error {
+	fmt.Printf("=== Building Config Routing Topology ===\n")
+
+	clusterBy, err := analyzer.ParseClusterBy(clusterBy)
+	if err != nil {
+		return err
+	}
+
+	topology, err := exporter.ExportTopology(ctx, configPath, clusterBy)
+	if err != nil {
+		return err
+	}
+
+	journeys, siblings := 0, 0
+	for _, edge := range topology.Edges {
+		if edge.Kind == analyzer.TopologyEdgeABSibling {
+			siblings++
+		} else {
+			journeys++
+		}
+	}
+	fmt.Printf("%d configs, %d journeys, %d A/B sibling links\n", len(topology.Nodes), journeys, siblings)
+	fmt.Printf("Render it with: dot -Tsvg %s -o config_topology.svg\n", exporter.Layout().TopologyDOT())
+	return nil
+}
+
+func runTrafficSplit(ctx context.Context, exporter *export.Exporter, configID int, configPath string, users int, seed, weightsFlag string) error {
+	fmt.Printf("=== Running Traffic Split Simulation ===\n")
+
+	weights, err := analyzer.ParseWeightOverrides(weightsFlag)
 	if err != nil {
-		return fmt.Errorf("failed to find A/B testing groups: %w", err)
+		return err
 	}
 
-	fmt.Printf("Found %d A/B testing groups\n", len(groups))
+	results, err := exporter.ExportTrafficSplit(ctx, configID, configPath, analyzer.TrafficSplitOptions{
+		Users:   users,
+		Seed:    seed,
+		Weights: weights,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("Config %d is not part of an A/B testing group\n", configID)
+	}
+	for _, result := range results {
+		fmt.Printf("Group %s (%d users):\n", result.Group, result.Users)
+		for _, v := range result.Variants {
+			fmt.Printf("  Config %d  weight %-4d expected %5.1f%%  observed %5.1f%% (%d users)\n",
+				v.ConfigID, v.Weight, v.ExpectedShare*100, v.ObservedShare*100, v.Users)
+		}
+		fmt.Printf("  chi-square %.3f (df %d, p-value %.4f)\n", result.ChiSquare, result.DegreesOfFreedom, result.PValue)
+		if len(weights) > 0 {
+			fmt.Printf("  %d users (%.1f%%) would switch variant with the proposed weights\n",
+				result.Reassigned, float64(result.Reassigned)/float64(result.Users)*100)
+		}
+	}
+
+	return nil
+}
+
+func runConfigDiff(ctx context.Context, exporter *export.Exporter, configPath, baseSpec, headLabel, gitRepo string) error {
+	fmt.Printf("=== Running Config Diff ===\n")
+
+	if baseSpec == "" {
+		return fmt.Errorf("-diff-base is required in diff mode")
+	}
+
+	base, err := newConfigProvider(ctx, baseSpec, gitRepo)
+	if err != nil {
+		return err
+	}
+
+	diff, err := exporter.ExportConfigDiff(ctx, base, configPath, baseSpec, headLabel)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(diff.String())
+	return nil
+}
 
-	// Export results
-	outputDir := filepath.Join(outputPath, fmt.Sprintf("%d", configID))
-	filename := filepath.Join(outputDir, fmt.Sprintf("ab_testing_analysis_%d_%s.json", configID, leadSource))
+func runConfigHistory(ctx context.Context, exporter *export.Exporter, configID int, leadSource string) error {
+	fmt.Printf("=== Running Config History ===\n")
 
-	// TODO: Implement export logic using the new service
-	fmt.Printf("Results would be exported to: %s\n", filename)
+	history, err := exporter.ExportConfigHistory(ctx, configID, leadSource)
+	if err != nil {
+		return err
+	}
 
+	fmt.Print(history.String())
 	return nil
 }
 
-func runJourneyAnalysis(ctx context.Context, service *analyzer.AnalyzerService, configID int, leadSource, configPath, outputPath string) error {
+func runConfigBlame(ctx context.Context, exporter *export.Exporter, configID int, leadSource string) error {
+	fmt.Printf("=== Running Config Blame ===\n")
+
+	blame, err := exporter.ExportConfigBlame(ctx, configID, leadSource)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(blame.String())
+	return nil
+}
+
+// newConfigProvider creates the provider of a config root, or of a ref of gitRepo when spec is "git:<ref>"
+func newConfigProvider(ctx context.Context, spec, gitRepo string) (config.ConfigProvider, error) {
+	ref, isGit := strings.CutPrefix(spec, "git:")
+	if !isGit {
+		return config.NewCachedConfigProvider(spec, 0), nil
+	}
+
+	provider, err := config.NewGitConfigProvider(ctx, gitRepo, ref, config.LenderConfigsRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+func runJourneyAnalysis(ctx context.Context, exporter *export.Exporter, configID int, leadSource, configPath string) error {
 	fmt.Printf("=== Running Journey Analysis ===\n")
 
-	// Find related configs
-	relatedConfigs, err := service.SearchRelatedConfigs(ctx, configID, leadSource, configPath)
+	template, err := exporter.ExportJourneyAnalysis(ctx, configID, leadSource, configPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d journeys\n", len(template.Journeys))
+	return nil
+}
+
+func runUIVersionMapping(ctx context.Context, exporter *export.Exporter, configID int, leadSource, configPath string) error {
+	fmt.Printf("=== Exporting UI Version Mapping ===\n")
+
+	mapping, err := exporter.ExportUIVersionMapping(ctx, configID, leadSource, configPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d step UI versions\n", len(mapping.Rows))
+	return nil
+}
+
+func runSimulation(ctx context.Context, exporter *export.Exporter, configID int, leadSource, configPath, attrsFlag string) error {
+	fmt.Printf("=== Running Journey Simulation ===\n")
+
+	attrs, err := condition.ParseAttributes(attrsFlag)
 	if err != nil {
-		return fmt.Errorf("failed to find related configs: %w", err)
+		return err
+	}
+	if _, ok := attrs["lead_source"]; !ok {
+		attrs["lead_source"] = leadSource
 	}
 
-	fmt.Printf("Found %d related configs\n", len(relatedConfigs))
+	sim, err := exporter.ExportSimulation(ctx, configID, leadSource, configPath, attrs)
+	if err != nil {
+		return err
+	}
 
-	// TODO: Implement journey generation using the new service
-	outputDir := filepath.Join(outputPath, fmt.Sprintf("%d", configID))
-	filename := filepath.Join(outputDir, fmt.Sprintf("journey_analysis_%d_%s.json", configID, leadSource))
-	fmt.Printf("Results would be exported to: %s\n", filename)
+	fmt.Printf("Journey: %s (%s) - %s\n", sim.JourneyID, sim.FlowType, sim.JourneyReason)
+	for i, step := range sim.Steps {
+		fmt.Printf("  %2d. %-30s %-25s %s\n", i+1, step.Name, step.UIVersion(), step.Reason)
+	}
+	for _, warning := range sim.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
 
 	return nil
 }
 
-func runCompleteAnalysis(ctx context.Context, service *analyzer.AnalyzerService, configID int, leadSource, configPath, outputPath string) error {
+func runRouting(ctx context.Context, service *analyzer.AnalyzerService, leadSource, configPath, attrsFlag string) error {
+	fmt.Printf("=== Resolving Routing ===\n")
+
+	tags, err := condition.ParseAttributes(attrsFlag)
+	if err != nil {
+		return err
+	}
+	if _, ok := tags["lead_source"]; !ok {
+		tags["lead_source"] = leadSource
+	}
+
+	result, err := service.ResolveRouting(ctx, configPath, tags)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Groups) == 0 {
+		fmt.Printf("No eligible config\n")
+	}
+	for i, group := range result.Groups {
+		fmt.Printf("%d. %s (score %d)\n", i+1, group.Name, group.Score)
+		for _, candidate := range group.Candidates {
+			fmt.Printf("     %d  %-20s %-10s weight %-4d %5.1f%%\n",
+				candidate.ConfigID, candidate.FlowType, candidate.UIVersion, candidate.Weight, candidate.Probability*100)
+		}
+	}
+	for _, excluded := range result.Excluded {
+		fmt.Printf("Excluded %d (%s): %s\n", excluded.ConfigID, excluded.Name, excluded.Reason)
+	}
+
+	return nil
+}
+
+func runCompleteAnalysis(ctx context.Context, exporter *export.Exporter, configID int, leadSource, configPath string) error {
 	fmt.Printf("=== Running Complete Analysis ===\n")
 
 	// Run A/B testing analysis
-	if err := runABTestingAnalysis(ctx, service, configID, leadSource, configPath, outputPath); err != nil {
+	if err := runABTestingAnalysis(ctx, exporter, configID, leadSource, configPath); err != nil {
 		return fmt.Errorf("A/B testing analysis failed: %w", err)
 	}
 
 	// Run journey analysis
-	if err := runJourneyAnalysis(ctx, service, configID, leadSource, configPath, outputPath); err != nil {
+	if err := runJourneyAnalysis(ctx, exporter, configID, leadSource, configPath); err != nil {
 		return fmt.Errorf("journey analysis failed: %w", err)
 	}
 
+	// Export the step-to-UI-version spreadsheets
+	if err := runUIVersionMapping(ctx, exporter, configID, leadSource, configPath); err != nil {
+		return fmt.Errorf("UI version mapping failed: %w", err)
+	}
+
+	// Generate summary report
+	if err := exporter.GenerateSummaryReport(configID, leadSource); err != nil {
+		return fmt.Errorf("summary report failed: %w", err)
+	}
+
+	// Generate the offline HTML report
+	if err := exporter.GenerateHTMLReport(configID, leadSource); err != nil {
+		return fmt.Errorf("HTML report failed: %w", err)
+	}
+
 	return nil
 }
 
@@ -160,7 +550,32 @@ OPTIONS:
     -lead-source <src>  Lead source type (default: "organic")
     -config-path <path> Path to lender configs directory (default: "evo")
     -output <path>      Output directory for results (default: "../../out/test_results")
-    -mode <mode>        Analysis mode: complete, ab-testing, journey (default: "complete")
+    -mode <mode>        Analysis mode: complete, ab-testing, ab-health, traffic, journey, mapping, simulate,
+                        route, diff, history, blame, lint, topology (default: "complete")
+    -attrs <k=v,...>    User attributes for simulate/route mode (lead_source defaults to -lead-source)
+    -ab-grouping <spec> A/B grouping strategy: name, tags, experiment; "+" requires all, "," tries
+                        alternatives in order (default: "name+tags")
+    -ab-weight-total <n> Total weight each A/B group must add up to in ab-health mode (default: 100);
+                        0 treats weights as relative shares (1/1 and 50/50 both split evenly)
+    -users <n>          Number of synthetic users in traffic mode (default: 10000)
+    -seed <s>           Seed of the user hash in traffic mode
+    -weights <id=w,...> Proposed weights to preview in traffic mode
+    -git-repo <path>    digital_journey git repository (default: "scripts/submodules/digital_journey")
+    -git-ref <ref>      Read configs from a branch, tag or commit of -git-repo without checking it out
+    -diff-base <snap>   Old snapshot in diff mode: a config root or git:<ref>
+    -diff-head <snap>   New snapshot in diff mode: a config root or git:<ref> (default: current configs)
+    -step-templates <f> Step template file (JSON) overriding the built-in journey steps
+    -step-catalogue <f> Step catalogue file (JSON) overriding the known ui_flow steps, their decision
+                        flag, allowed successors and default UI version
+    -lint-policy <f>    Lint policy file (JSON) disabling rules globally or per folder
+    -lint-disable <ids> Comma-separated lint rule IDs to disable
+    -cluster-by <tag>   Cluster the topology diagram by lead_source or product_code, or "" for none
+                        (default: "lead_source")
+    -diagram-format <f> Comma-separated diagram formats: plantuml, mermaid (default: "plantuml")
+    -image-renderer <r> PNG renderer: auto uses PlantUML when java and plantuml.jar are available and
+                        the built-in Go renderer otherwise; plantuml or builtin force one (default: "auto")
+    -load-mode <mode>   Unreadable or malformed config files and duplicate config IDs: lenient warns
+                        (skipping the broken files), strict fails the run (default: "lenient")
     -help               Show this help message
 
 EXAMPLES:
@@ -170,13 +585,74 @@ EXAMPLES:
     # A/B testing analysis only
     ui-version-check -config 9054 -mode ab-testing
 
+    # Resolve the UI version of every step for a given user
+    ui-version-check -config 9054 -mode simulate -attrs telco_code=viettel,communication_call=success
+
+    # Which configs does a user land on, and with what probability
+    ui-version-check -mode route -attrs telco_code=viettel,product_code=evo
+
+    # Group A/B variants by experiment tag, falling back to the critical tag signature
+    ui-version-check -config 9054 -mode ab-testing -ab-grouping experiment,tags
+
+    # Fail (exit code 1) when A/B traffic weights are misconfigured
+    ui-version-check -mode ab-health -config-path evo
+
+    # Preview how a weight change shifts traffic between A/B variants
+    ui-version-check -config 9054 -mode traffic -weights 9054=60,9055=40
+
+    # What changed in the evo configs between two checkouts
+    ui-version-check -mode diff -diff-base ../old/lender_configs -diff-head ../new/lender_configs
+
+    # Compare two digital_journey releases straight from git
+    ui-version-check -mode diff -diff-base git:v1.2.3 -diff-head git:v1.3.0
+
+    # Analyze configs as of a tag
+    ui-version-check -config 9054 -git-ref v1.2.3
+
+    # When did the steps of config 9054 change, and which commit introduced each step
+    ui-version-check -config 9054 -mode history
+    ui-version-check -config 9054 -mode blame
+
+    # Validate the evo configs before shipping (exit code 1 on errors)
+    ui-version-check -mode lint -config-path evo -lint-disable ui-flow-settings
+
+    # Lint and generate journeys against a custom step catalogue
+    ui-version-check -mode lint -step-catalogue ./my_step_catalogue.json
+
+    # Routing graph of every evo config as Graphviz DOT, clustered by product_code
+    ui-version-check -mode topology -config-path evo -cluster-by product_code
+
+    # Fail instead of skipping config files with JSON errors
+    ui-version-check -config 9054 -load-mode strict
+
+    # Write Mermaid diagrams for GitHub and the wiki next to the PlantUML ones
+    ui-version-check -config 9054 -diagram-format plantuml,mermaid
+
+    # Render PNG/SVG diagrams in Go, without Java or plantuml.jar
+    ui-version-check -config 9054 -image-renderer builtin
+
+    # Step-to-UI-version spreadsheets (CSV and XLSX) for QA coverage tracking
+    ui-version-check -config 9054 -mode mapping
+
     # Custom paths
     ui-version-check -config 9054 -config-path win -output ./results
 
 MODES:
-    complete    - Full analysis including A/B testing, journey mapping, and visualization
+    complete    - Full analysis including A/B testing, journey mapping, visualization, the UI version
+                  spreadsheets and the HTML report
     ab-testing  - A/B testing detection and analysis only
+    ab-health   - Check A/B traffic weights; exits with code 1 on errors
+    traffic     - Simulate how users are bucketed into the A/B variants of the config
+    diff        - Compare the configs of two config roots (added/removed, ui_version, ui_flow, tags, weights,
+                  A/B groups)
+    history     - Commits of -git-repo (up to -git-ref, default HEAD) that changed the config
+    blame       - Commit that introduced each ui_flow step and last changed its ui_flow_settings
     journey     - Journey flow analysis and visualization only
+    mapping     - Every journey step and UI version as CSV and XLSX (one sheet per config, one for A/B groups)
+    simulate    - Resolve one concrete journey and the UI version of each step for -attrs
+    route       - Rank the configs a user with -attrs tags is eligible for, with traffic split
+    lint        - Check the configs of -config-path against the lint rule catalogue; exits with code 1 on errors
+    topology    - Routing graph of all configs of -config-path (journeys and A/B siblings) as JSON and DOT
 
 FEATURES:
     ✅ Local file-based configuration loading
@@ -185,7 +661,7 @@ FEATURES:
     ✅ No external dependencies
 
 OUTPUT:
-    The tool generates JSON data files, PlantUML diagrams, PNG images, and summary reports
-    in the specified output directory.
+    The tool generates JSON data files, PlantUML diagrams, SVG and PNG images, CSV and XLSX
+    spreadsheets, and Markdown and self-contained HTML reports in the specified output directory.
 `)
 }
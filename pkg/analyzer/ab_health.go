@@ -0,0 +1,271 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// DefaultABWeightTotal is the total weight the variants of an A/B group are expected to add up to
+const DefaultABWeightTotal = 100
+
+// Severity of an A/B health finding
+type Severity string
+
+// Finding severities; errors make the health check fail
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// A/B health finding codes
+const (
+	FindingOverAllocated  = "over_allocated"
+	FindingUnderAllocated = "under_allocated"
+	FindingZeroWeight     = "zero_weight"
+	FindingSingleVariant  = "single_variant"
+	FindingWeightMismatch = "weight_mismatch"
+)
+
+// ABHealthFinding is one problem found in the traffic weights of an A/B group
+type ABHealthFinding struct {
+	Code      string   `json:"code"`
+	Severity  Severity `json:"severity"`
+	Group     string   `json:"group"`
+	ConfigIDs []int    `json:"config_ids"`
+	Message   string   `json:"message"`
+}
+
+// ABHealthReport is the result of an A/B traffic weight health check
+type ABHealthReport struct {
+	Strategy      string            `json:"strategy"`
+	ExpectedTotal int               `json:"expected_total,omitempty"`
+	Groups        int               `json:"groups"`
+	Findings      []ABHealthFinding `json:"findings"`
+}
+
+// HasErrors reports whether any finding has error severity
+func (r *ABHealthReport) HasErrors() bool {
+	for _, finding := range r.Findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckABHealth checks the traffic weights of the buckets formed by strategy against
+// expectedTotal, usually DefaultABWeightTotal. An expectedTotal of 0 treats weights as
+// relative shares (1/1 and 50/50 both split traffic evenly) and skips the total checks.
+//
+// Unlike FindAllABTestingGroupsWith, buckets here keep every config the strategy groups,
+// including zero-weight ones, so that misconfigured variants are visible:
+//   - a bucket whose weights do not add up to expectedTotal is over or under allocated
+//   - a zero-weight config receives no traffic
+//   - a bucket of 2+ configs where only one has a positive weight is a single-variant group
+//   - buckets testing the same UI flows for different lead sources must split traffic the same way
+func CheckABHealth(strategy GroupingStrategy, configs []*config.LenderConfig, expectedTotal int) *ABHealthReport {
+	if expectedTotal < 0 {
+		expectedTotal = 0
+	}
+
+	report := &ABHealthReport{
+		Strategy:      strategy.Name(),
+		ExpectedTotal: expectedTotal,
+		Findings:      []ABHealthFinding{},
+	}
+
+	buckets := bucketConfigs(strategy, configs)
+	for _, bucket := range buckets {
+		name := bucket[0].Name
+		ids := configIDs(bucket)
+
+		total := 0
+		for _, cfg := range bucket {
+			total += cfg.Weight
+		}
+
+		if len(bucket) == 1 {
+			cfg := bucket[0]
+			// A lone config gets all the traffic of its bucket whatever its weight
+			switch {
+			case cfg.Weight <= 0:
+				report.addFinding(FindingZeroWeight, SeverityWarning, name, ids,
+					fmt.Sprintf("config %d has weight %d and receives no traffic", cfg.ID, cfg.Weight))
+			case expectedTotal > 0 && cfg.Weight > expectedTotal:
+				report.addFinding(FindingOverAllocated, SeverityError, name, ids,
+					fmt.Sprintf("config %d has weight %d, above the expected total %d", cfg.ID, cfg.Weight, expectedTotal))
+			}
+			continue
+		}
+
+		report.Groups++
+
+		switch {
+		case expectedTotal == 0:
+		case total > expectedTotal:
+			report.addFinding(FindingOverAllocated, SeverityError, name, ids,
+				fmt.Sprintf("weights of configs %s add up to %d, above the expected total %d", joinIDs(ids), total, expectedTotal))
+		case total < expectedTotal:
+			report.addFinding(FindingUnderAllocated, SeverityError, name, ids,
+				fmt.Sprintf("weights of configs %s add up to %d, below the expected total %d", joinIDs(ids), total, expectedTotal))
+		}
+
+		var active []int
+		for _, cfg := range bucket {
+			if cfg.Weight <= 0 {
+				report.addFinding(FindingZeroWeight, SeverityError, name, []int{cfg.ID},
+					fmt.Sprintf("variant %d has weight %d and receives no traffic", cfg.ID, cfg.Weight))
+				continue
+			}
+			active = append(active, cfg.ID)
+		}
+		if len(active) == 1 {
+			report.addFinding(FindingSingleVariant, SeverityWarning, name, ids,
+				fmt.Sprintf("only variant %d of configs %s has a positive weight and gets all the traffic", active[0], joinIDs(ids)))
+		}
+	}
+
+	report.checkLeadSourceWeights(buckets)
+
+	return report
+}
+
+// checkLeadSourceWeights compares the traffic split of buckets testing the same set of UI
+// flows for different lead sources
+func (r *ABHealthReport) checkLeadSourceWeights(buckets [][]*config.LenderConfig) {
+	type experiment struct {
+		leadSource string
+		bucket     []*config.LenderConfig
+		weights    map[string]int
+	}
+
+	experiments := make(map[string][]experiment)
+	var keys []string
+	for _, bucket := range buckets {
+		if len(bucket) < 2 {
+			continue
+		}
+
+		weights := make(map[string]int)
+		var flows []string
+		for _, cfg := range bucket {
+			flow := strings.Join(cfg.UIFlow, ">")
+			weights[flow] += cfg.Weight
+			flows = append(flows, flow)
+		}
+		sort.Strings(flows)
+		key := strings.Join(flows, "|")
+
+		if _, ok := experiments[key]; !ok {
+			keys = append(keys, key)
+		}
+		experiments[key] = append(experiments[key], experiment{
+			leadSource: strings.Join(tagValues(bucket[0].Tags, "lead_source"), ","),
+			bucket:     bucket,
+			weights:    weights,
+		})
+	}
+
+	for _, key := range keys {
+		group := experiments[key]
+		reference := group[0]
+		for _, other := range group[1:] {
+			if other.leadSource == reference.leadSource || weightsEqual(reference.weights, other.weights) {
+				continue
+			}
+
+			ids := append(configIDs(reference.bucket), configIDs(other.bucket)...)
+			r.addFinding(FindingWeightMismatch, SeverityWarning, reference.bucket[0].Name, ids,
+				fmt.Sprintf("lead_source %s (configs %s) splits traffic as %s but lead_source %s (configs %s) as %s",
+					reference.leadSource, joinIDs(configIDs(reference.bucket)), weightSplit(reference.bucket),
+					other.leadSource, joinIDs(configIDs(other.bucket)), weightSplit(other.bucket)))
+		}
+	}
+}
+
+func (r *ABHealthReport) addFinding(code string, severity Severity, group string, ids []int, message string) {
+	r.Findings = append(r.Findings, ABHealthFinding{
+		Code:      code,
+		Severity:  severity,
+		Group:     group,
+		ConfigIDs: ids,
+		Message:   message,
+	})
+}
+
+// bucketConfigs puts each config in the first bucket whose first config the strategy matches
+func bucketConfigs(strategy GroupingStrategy, configs []*config.LenderConfig) [][]*config.LenderConfig {
+	var buckets [][]*config.LenderConfig
+	for _, cfg := range configs {
+		placed := false
+		for i, bucket := range buckets {
+			if _, ok := strategy.Match(bucket[0], cfg); ok {
+				buckets[i] = append(buckets[i], cfg)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			buckets = append(buckets, []*config.LenderConfig{cfg})
+		}
+	}
+	return buckets
+}
+
+func configIDs(configs []*config.LenderConfig) []int {
+	ids := make([]int, 0, len(configs))
+	for _, cfg := range configs {
+		ids = append(ids, cfg.ID)
+	}
+	return ids
+}
+
+func joinIDs(ids []int) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%d", id))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// weightSplit renders the weights of a bucket, e.g. "50/50"
+func weightSplit(bucket []*config.LenderConfig) string {
+	parts := make([]string, 0, len(bucket))
+	for _, cfg := range bucket {
+		parts = append(parts, fmt.Sprintf("%d", cfg.Weight))
+	}
+	return strings.Join(parts, "/")
+}
+
+// weightsEqual reports whether two weight maps split traffic in the same shares
+func weightsEqual(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	totalA, totalB := 0, 0
+	for k, v := range a {
+		totalA += v
+		totalB += b[k]
+	}
+	for k, v := range a {
+		w, ok := b[k]
+		if !ok || v*totalB != w*totalA {
+			return false
+		}
+	}
+	return true
+}
+
+// tagValues returns all values of a tag
+func tagValues(tags []config.Tag, name string) []string {
+	var values []string
+	for _, tag := range tags {
+		if tag.Name == name {
+			values = append(values, tag.Value)
+		}
+	}
+	return values
+}
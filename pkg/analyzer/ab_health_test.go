@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func TestCheckABHealth(t *testing.T) {
+	variant := func(id int, name, leadSource string, weight int, flow ...string) *config.LenderConfig {
+		return &config.LenderConfig{
+			ID:     id,
+			Name:   name,
+			Weight: weight,
+			UIFlow: flow,
+			Tags:   []config.Tag{{Name: "lead_source", Value: leadSource}, {Name: "flow_type", Value: "auto"}},
+		}
+	}
+
+	configs := []*config.LenderConfig{
+		// Healthy 50/50 test for organic
+		variant(1, "auto.organic", "organic", 50, "otp", "esign.review"),
+		variant(2, "auto.organic", "organic", 50, "otp", "ekyc.nfc", "esign.review"),
+		// Same flows for paid, split 70/40: over allocated and not matching organic
+		variant(3, "auto.paid", "paid", 70, "otp", "esign.review"),
+		variant(4, "auto.paid", "paid", 40, "otp", "ekyc.nfc", "esign.review"),
+		// Zero-weight variant leaves the group under allocated with a single variant
+		variant(5, "semi.organic", "organic", 80, "otp"),
+		variant(6, "semi.organic", "organic", 0, "otp", "ekyc.nfc"),
+		// Lone config with a partial weight still gets all of its traffic
+		variant(7, "manual.organic", "organic", 30, "otp"),
+		// Normal config
+		variant(8, "cif.organic", "organic", 100, "cif.confirm"),
+	}
+
+	report := CheckABHealth(DefaultGroupingStrategy(), configs, 100)
+
+	if report.ExpectedTotal != 100 || report.Groups != 3 {
+		t.Fatalf("unexpected report header: %+v", report)
+	}
+
+	codes := make(map[string][]int)
+	for _, finding := range report.Findings {
+		codes[finding.Code] = append(codes[finding.Code], finding.ConfigIDs...)
+	}
+
+	expected := map[string][]int{
+		FindingOverAllocated:  {3, 4},
+		FindingUnderAllocated: {5, 6},
+		FindingZeroWeight:     {6},
+		FindingSingleVariant:  {5, 6},
+		FindingWeightMismatch: {1, 2, 3, 4},
+	}
+	for code, ids := range expected {
+		if len(codes[code]) != len(ids) {
+			t.Errorf("%s: expected configs %v, got %v", code, ids, codes[code])
+			continue
+		}
+		for i, id := range ids {
+			if codes[code][i] != id {
+				t.Errorf("%s: expected configs %v, got %v", code, ids, codes[code])
+				break
+			}
+		}
+	}
+	if len(report.Findings) != len(expected) {
+		t.Errorf("expected %d findings, got %+v", len(expected), report.Findings)
+	}
+
+	if !report.HasErrors() {
+		t.Error("expected the report to have errors")
+	}
+	if CheckABHealth(DefaultGroupingStrategy(), configs[:2], DefaultABWeightTotal).HasErrors() {
+		t.Error("expected a balanced group to pass")
+	}
+}
+
+func TestCheckABHealthDefaultTotal(t *testing.T) {
+	variant := func(id, weight int) *config.LenderConfig {
+		return &config.LenderConfig{ID: id, Name: "auto", Weight: weight, UIFlow: []string{"otp"},
+			Tags: []config.Tag{{Name: "lead_source", Value: "organic"}}}
+	}
+
+	// 60/60 exceeds the default total of 100
+	report := CheckABHealth(DefaultGroupingStrategy(), []*config.LenderConfig{variant(1, 60), variant(2, 60)}, DefaultABWeightTotal)
+	if report.ExpectedTotal != 100 || len(report.Findings) != 1 || report.Findings[0].Code != FindingOverAllocated {
+		t.Errorf("expected the group to be over allocated against 100, got %+v", report)
+	}
+
+	// 100/0: all the traffic goes to one variant
+	report = CheckABHealth(DefaultGroupingStrategy(), []*config.LenderConfig{variant(1, 100), variant(2, 0), variant(3, 0)}, DefaultABWeightTotal)
+	var single []ABHealthFinding
+	for _, finding := range report.Findings {
+		if finding.Code == FindingSingleVariant {
+			single = append(single, finding)
+		}
+	}
+	if len(single) != 1 || single[0].Severity != SeverityWarning || len(single[0].ConfigIDs) != 3 {
+		t.Errorf("expected one single_variant warning for the group, got %+v", report.Findings)
+	}
+
+	// A lone config is not an A/B group
+	if report := CheckABHealth(DefaultGroupingStrategy(), []*config.LenderConfig{variant(1, 50)}, DefaultABWeightTotal); len(report.Findings) != 0 {
+		t.Errorf("expected no findings for a lone config, got %+v", report.Findings)
+	}
+}
+
+func TestCheckABHealthRelativeWeights(t *testing.T) {
+	// Shape of the reference data: every config has weight 1
+	variant := func(id int, leadSource string, flow ...string) *config.LenderConfig {
+		return &config.LenderConfig{
+			ID:     id,
+			Name:   "v1.0.collect." + leadSource,
+			Weight: 1,
+			UIFlow: flow,
+			Tags:   []config.Tag{{Name: "lead_source", Value: leadSource}, {Name: "flow_type", Value: "collect"}},
+		}
+	}
+	configs := []*config.LenderConfig{
+		variant(9054, "organic", "otp", "app_form.basic_info"),
+		variant(9101, "organic", "otp", "app_form.personal_info"),
+		// Same flows split 50/50 for paid: the same shares as 1/1
+		{ID: 9201, Name: "v1.0.collect.paid", Weight: 50, UIFlow: []string{"otp", "app_form.basic_info"},
+			Tags: []config.Tag{{Name: "lead_source", Value: "paid"}, {Name: "flow_type", Value: "collect"}}},
+		{ID: 9202, Name: "v1.0.collect.paid", Weight: 50, UIFlow: []string{"otp", "app_form.personal_info"},
+			Tags: []config.Tag{{Name: "lead_source", Value: "paid"}, {Name: "flow_type", Value: "collect"}}},
+		// Lone configs, whatever their weight
+		variant(9012, "organic", "cif.confirm"),
+		{ID: 9013, Name: "semi", Weight: 75, UIFlow: []string{"otp"}},
+	}
+
+	report := CheckABHealth(DefaultGroupingStrategy(), configs, 0)
+	if report.Groups != 2 || len(report.Findings) != 0 || report.ExpectedTotal != 0 {
+		t.Errorf("expected two healthy groups without findings, got %+v", report)
+	}
+
+	// An explicit total still flags the 1/1 group
+	report = CheckABHealth(DefaultGroupingStrategy(), configs[:2], 100)
+	if len(report.Findings) != 1 || report.Findings[0].Code != FindingUnderAllocated || !report.HasErrors() {
+		t.Errorf("expected the group to be under allocated against 100, got %+v", report.Findings)
+	}
+}
@@ -1,23 +1,26 @@
 package analyzer
 
 import (
-	"fmt"
-
 	"github.com/tsocial/ui-version-mapping/pkg/config"
 )
 
+// SearchTypeABTestingAnalysis is the search type of an A/B testing analysis result
+const SearchTypeABTestingAnalysis = "ab_testing_analysis"
+
 // ABTestingVariant represents an A/B testing variant
 type ABTestingVariant struct {
-	ConfigID    int      `json:"config_id"`
-	Name        string   `json:"name"`
-	Weight      int      `json:"weight"`
-	UIFlow      []string `json:"ui_flow"`
-	Differences []string `json:"differences"`
+	ConfigID    int                 `json:"config_id"`
+	Name        string              `json:"name"`
+	Weight      int                 `json:"weight"`
+	UIFlow      []string            `json:"ui_flow"`
+	Differences []string            `json:"differences"`
+	FlowDiff    []FlowDiffOperation `json:"flow_diff,omitempty"`
 }
 
 // ABTestingGroup represents a group of A/B testing variants
 type ABTestingGroup struct {
 	GroupName   string             `json:"group_name"`
+	Strategy    string             `json:"strategy,omitempty"`
 	Variants    []ABTestingVariant `json:"variants"`
 	TotalWeight int                `json:"total_weight"`
 }
@@ -31,54 +34,73 @@ type ABTestingAnalysisResult struct {
 	TotalResults    int                          `json:"total_results"`
 }
 
-// DetectABTestingVariants finds A/B testing variants of a config
+// DetectABTestingVariants finds A/B testing variants of a config using the default grouping strategy
 func DetectABTestingVariants(sourceConfig *config.LenderConfig, allConfigs []*config.LenderConfig) []ABTestingVariant {
+	variants, _ := DetectABTestingVariantsWith(DefaultGroupingStrategy(), sourceConfig, allConfigs)
+	return variants
+}
+
+// DetectABTestingVariantsWith finds A/B testing variants of a config using strategy.
+// It also returns the name of the rule that matched the first variant.
+func DetectABTestingVariantsWith(strategy GroupingStrategy, sourceConfig *config.LenderConfig, allConfigs []*config.LenderConfig) ([]ABTestingVariant, string) {
 	var variants []ABTestingVariant
+	matchedBy := ""
 
 	for _, cfg := range allConfigs {
 		if cfg.ID == sourceConfig.ID {
 			continue
 		}
 
-		// Check if configs have same basic conditions but different UI flows
-		if IsABTestingVariant(sourceConfig, cfg) {
-			differences := FindUIFlowDifferences(sourceConfig.UIFlow, cfg.UIFlow)
-			variants = append(variants, ABTestingVariant{
-				ConfigID:    cfg.ID,
-				Name:        cfg.Name,
-				Weight:      cfg.Weight,
-				UIFlow:      cfg.UIFlow,
-				Differences: differences,
-			})
+		// Check if configs belong to the same experiment but have different UI flows
+		matched, ok := IsABTestingVariantWith(strategy, sourceConfig, cfg)
+		if !ok {
+			continue
+		}
+		if matchedBy == "" {
+			matchedBy = matched
 		}
+
+		flowDiff := DiffUIFlows(sourceConfig.UIFlow, cfg.UIFlow)
+		variants = append(variants, ABTestingVariant{
+			ConfigID:    cfg.ID,
+			Name:        cfg.Name,
+			Weight:      cfg.Weight,
+			UIFlow:      cfg.UIFlow,
+			Differences: FlowDiffStrings(flowDiff),
+			FlowDiff:    flowDiff,
+		})
 	}
 
-	return variants
+	return variants, matchedBy
 }
 
-// IsABTestingVariant checks if 2 configs are A/B testing variants
+// IsABTestingVariant checks if 2 configs are A/B testing variants using the default grouping strategy
 func IsABTestingVariant(config1, config2 *config.LenderConfig) bool {
-	// 1. Must have same name (same business logic)
-	if config1.Name != config2.Name {
-		return false
-	}
+	_, ok := IsABTestingVariantWith(DefaultGroupingStrategy(), config1, config2)
+	return ok
+}
 
-	// 2. Must have same basic tags
-	if !HasSameBasicTags(config1, config2) {
-		return false
+// IsABTestingVariantWith checks if 2 configs are A/B testing variants: the strategy must group
+// them, and they must have different UI flows and positive weights. The first result is the
+// name of the rule that grouped them.
+func IsABTestingVariantWith(strategy GroupingStrategy, config1, config2 *config.LenderConfig) (string, bool) {
+	// 1. Must belong to the same experiment
+	matched, ok := strategy.Match(config1, config2)
+	if !ok {
+		return "", false
 	}
 
-	// 3. Must have different UI flows (this is the A/B test point)
+	// 2. Must have different UI flows (this is the A/B test point)
 	if AreUIFlowsIdentical(config1.UIFlow, config2.UIFlow) {
-		return false
+		return "", false
 	}
 
-	// 4. Usually have weight > 0 (for traffic distribution)
+	// 3. Usually have weight > 0 (for traffic distribution)
 	if config1.Weight <= 0 || config2.Weight <= 0 {
-		return false
+		return "", false
 	}
 
-	return true
+	return matched, true
 }
 
 // HasSameBasicTags checks if 2 configs have same basic tags
@@ -159,39 +181,9 @@ func AreUIFlowsIdentical(flow1, flow2 []string) bool {
 	return true
 }
 
-// FindUIFlowDifferences finds differences between 2 UI flows
+// FindUIFlowDifferences finds differences between 2 UI flows as human readable strings
 func FindUIFlowDifferences(flow1, flow2 []string) []string {
-	var differences []string
-
-	// Find steps that are different
-	maxLen := len(flow1)
-	if len(flow2) > maxLen {
-		maxLen = len(flow2)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		var step1, step2 string
-
-		if i < len(flow1) {
-			step1 = flow1[i]
-		}
-
-		if i < len(flow2) {
-			step2 = flow2[i]
-		}
-
-		if step1 != step2 {
-			if step1 != "" && step2 != "" {
-				differences = append(differences, fmt.Sprintf("Step %d: %s vs %s", i+1, step1, step2))
-			} else if step1 != "" {
-				differences = append(differences, fmt.Sprintf("Step %d: %s (missing in variant)", i+1, step1))
-			} else {
-				differences = append(differences, fmt.Sprintf("Step %d: %s (extra in variant)", i+1, step2))
-			}
-		}
-	}
-
-	return differences
+	return FlowDiffStrings(DiffUIFlows(flow1, flow2))
 }
 
 // GetFlowTypeFromTagsMap gets flow_type from tags map (prioritizes esign_flow_type first)
@@ -209,8 +201,14 @@ func GetFlowTypeFromTagsMap(tagsMap map[string][]string) string {
 	return "unknown"
 }
 
-// FindAllABTestingGroups finds all A/B testing groups in a set of configs
+// FindAllABTestingGroups finds all A/B testing groups in a set of configs using the default grouping strategy
 func FindAllABTestingGroups(allConfigs []*config.LenderConfig) []ABTestingGroup {
+	return FindAllABTestingGroupsWith(DefaultGroupingStrategy(), allConfigs)
+}
+
+// FindAllABTestingGroupsWith finds all A/B testing groups in a set of configs using strategy;
+// each group records the strategy rule that matched it
+func FindAllABTestingGroupsWith(strategy GroupingStrategy, allConfigs []*config.LenderConfig) []ABTestingGroup {
 	var groups []ABTestingGroup
 	processedConfigs := make(map[int]bool)
 
@@ -219,11 +217,12 @@ func FindAllABTestingGroups(allConfigs []*config.LenderConfig) []ABTestingGroup
 			continue
 		}
 
-		variants := DetectABTestingVariants(cfg, allConfigs)
+		variants, matchedBy := DetectABTestingVariantsWith(strategy, cfg, allConfigs)
 		if len(variants) > 0 {
 			// Create A/B testing group
 			group := ABTestingGroup{
 				GroupName:   cfg.Name,
+				Strategy:    matchedBy,
 				TotalWeight: cfg.Weight,
 			}
 
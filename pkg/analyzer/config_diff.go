@@ -0,0 +1,344 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// ConfigSummary identifies a config added or removed between two snapshots
+type ConfigSummary struct {
+	ConfigID  int    `json:"config_id"`
+	Name      string `json:"name"`
+	UIVersion string `json:"ui_version"`
+	Weight    int    `json:"weight"`
+	File      string `json:"file,omitempty"`
+}
+
+// StringChange is a changed string value
+type StringChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// IntChange is a changed integer value
+type IntChange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// ABGroupMembership is the A/B group a config belongs to in a snapshot; an empty
+// Group means the config is not part of any group
+type ABGroupMembership struct {
+	Group    string `json:"group"`
+	Variants []int  `json:"variants"`
+}
+
+// ABGroupChange is a change of the A/B group a config belongs to
+type ABGroupChange struct {
+	From ABGroupMembership `json:"from"`
+	To   ABGroupMembership `json:"to"`
+}
+
+// ConfigChange lists what changed in a config present in both snapshots
+type ConfigChange struct {
+	ConfigID    int                 `json:"config_id"`
+	Name        string              `json:"name"`
+	NameChange  *StringChange       `json:"name_change,omitempty"`
+	UIVersion   *StringChange       `json:"ui_version,omitempty"`
+	UIFlow      []FlowDiffOperation `json:"ui_flow,omitempty"`
+	TagsAdded   []config.Tag        `json:"tags_added,omitempty"`
+	TagsRemoved []config.Tag        `json:"tags_removed,omitempty"`
+	Weight      *IntChange          `json:"weight,omitempty"`
+	ABGroup     *ABGroupChange      `json:"ab_group,omitempty"`
+	// StepSettings lists the steps whose ui_flow_settings changed
+	StepSettings []string `json:"step_settings,omitempty"`
+}
+
+// ConfigSnapshotDiff is the difference between two sets of configs
+type ConfigSnapshotDiff struct {
+	Base      string          `json:"base"`
+	Head      string          `json:"head"`
+	Added     []ConfigSummary `json:"added"`
+	Removed   []ConfigSummary `json:"removed"`
+	Changed   []ConfigChange  `json:"changed"`
+	Unchanged int             `json:"unchanged"`
+}
+
+// Empty reports whether the snapshots hold the same configs
+func (d *ConfigSnapshotDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffConfigProviders loads folderPath from two providers and diffs the configs.
+// baseLabel and headLabel name the snapshots in the result.
+func DiffConfigProviders(ctx context.Context, base, head config.ConfigProvider, folderPath, baseLabel, headLabel string, strategy GroupingStrategy) (*ConfigSnapshotDiff, error) {
+	baseConfigs, err := base.LoadConfigs(ctx, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base configs from %s: %w", baseLabel, err)
+	}
+
+	headConfigs, err := head.LoadConfigs(ctx, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load head configs from %s: %w", headLabel, err)
+	}
+
+	diff := DiffConfigSnapshots(baseConfigs, headConfigs, strategy)
+	diff.Base = baseLabel
+	diff.Head = headLabel
+	return diff, nil
+}
+
+// DiffConfigSnapshots compares configs by ID. A/B group membership is computed in each
+// snapshot with strategy (the default strategy when nil).
+func DiffConfigSnapshots(base, head []*config.LenderConfig, strategy GroupingStrategy) *ConfigSnapshotDiff {
+	if strategy == nil {
+		strategy = DefaultGroupingStrategy()
+	}
+
+	diff := &ConfigSnapshotDiff{
+		Added:   []ConfigSummary{},
+		Removed: []ConfigSummary{},
+		Changed: []ConfigChange{},
+	}
+
+	baseByID := configsByID(base)
+	headByID := configsByID(head)
+	baseGroups := groupMemberships(FindAllABTestingGroupsWith(strategy, base))
+	headGroups := groupMemberships(FindAllABTestingGroupsWith(strategy, head))
+
+	for _, id := range sortedConfigIDs(baseByID) {
+		if _, ok := headByID[id]; !ok {
+			diff.Removed = append(diff.Removed, summarizeConfig(baseByID[id]))
+		}
+	}
+
+	for _, id := range sortedConfigIDs(headByID) {
+		headConfig := headByID[id]
+		baseConfig, ok := baseByID[id]
+		if !ok {
+			diff.Added = append(diff.Added, summarizeConfig(headConfig))
+			continue
+		}
+
+		change := diffConfig(baseConfig, headConfig)
+		if !sameMembership(baseGroups[id], headGroups[id]) {
+			change.ABGroup = &ABGroupChange{From: baseGroups[id], To: headGroups[id]}
+		}
+
+		if change.isEmpty() {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changed = append(diff.Changed, change)
+	}
+
+	return diff
+}
+
+// String renders the diff for terminal output
+func (d *ConfigSnapshotDiff) String() string {
+	var out strings.Builder
+
+	out.WriteString(fmt.Sprintf("Config diff: %s -> %s\n", d.Base, d.Head))
+	if d.Empty() {
+		out.WriteString(fmt.Sprintf("No changes (%d configs)\n", d.Unchanged))
+		return out.String()
+	}
+
+	if len(d.Added) > 0 {
+		out.WriteString(fmt.Sprintf("Added (%d):\n", len(d.Added)))
+		for _, c := range d.Added {
+			out.WriteString(fmt.Sprintf("  + %d %s (ui_version %s, weight %d)\n", c.ConfigID, c.Name, c.UIVersion, c.Weight))
+		}
+	}
+
+	if len(d.Removed) > 0 {
+		out.WriteString(fmt.Sprintf("Removed (%d):\n", len(d.Removed)))
+		for _, c := range d.Removed {
+			out.WriteString(fmt.Sprintf("  - %d %s (ui_version %s, weight %d)\n", c.ConfigID, c.Name, c.UIVersion, c.Weight))
+		}
+	}
+
+	if len(d.Changed) > 0 {
+		out.WriteString(fmt.Sprintf("Changed (%d):\n", len(d.Changed)))
+		for _, c := range d.Changed {
+			out.WriteString(fmt.Sprintf("  ~ %d %s\n", c.ConfigID, c.Name))
+			if c.NameChange != nil {
+				out.WriteString(fmt.Sprintf("      name: %s -> %s\n", c.NameChange.From, c.NameChange.To))
+			}
+			if c.UIVersion != nil {
+				out.WriteString(fmt.Sprintf("      ui_version: %s -> %s\n", c.UIVersion.From, c.UIVersion.To))
+			}
+			for _, op := range c.UIFlow {
+				out.WriteString(fmt.Sprintf("      ui_flow: %s\n", op.Compact()))
+			}
+			for _, tag := range c.TagsAdded {
+				out.WriteString(fmt.Sprintf("      tag added: %s=%s\n", tag.Name, tag.Value))
+			}
+			for _, tag := range c.TagsRemoved {
+				out.WriteString(fmt.Sprintf("      tag removed: %s=%s\n", tag.Name, tag.Value))
+			}
+			if c.Weight != nil {
+				out.WriteString(fmt.Sprintf("      weight: %d -> %d\n", c.Weight.From, c.Weight.To))
+			}
+			if c.ABGroup != nil {
+				out.WriteString(fmt.Sprintf("      A/B group: %s -> %s\n", c.ABGroup.From, c.ABGroup.To))
+			}
+			for _, step := range c.StepSettings {
+				out.WriteString(fmt.Sprintf("      ui_flow_settings: %s\n", step))
+			}
+		}
+	}
+
+	out.WriteString(fmt.Sprintf("Unchanged: %d\n", d.Unchanged))
+	return out.String()
+}
+
+// String renders the membership as "name (id, id)" or "none"
+func (m ABGroupMembership) String() string {
+	if m.Group == "" {
+		return "none"
+	}
+	return fmt.Sprintf("%s (%s)", m.Group, joinIDs(m.Variants))
+}
+
+// diffConfig compares the fields of two versions of a config
+func diffConfig(base, head *config.LenderConfig) ConfigChange {
+	change := ConfigChange{
+		ConfigID: head.ID,
+		Name:     head.Name,
+	}
+
+	if base.Name != head.Name {
+		change.NameChange = &StringChange{From: base.Name, To: head.Name}
+	}
+	if base.UIVersion != head.UIVersion {
+		change.UIVersion = &StringChange{From: base.UIVersion, To: head.UIVersion}
+	}
+	change.UIFlow = DiffUIFlows(base.UIFlow, head.UIFlow)
+	change.TagsAdded = subtractTags(head.Tags, base.Tags)
+	change.TagsRemoved = subtractTags(base.Tags, head.Tags)
+	if base.Weight != head.Weight {
+		change.Weight = &IntChange{From: base.Weight, To: head.Weight}
+	}
+	change.StepSettings = changedStepSettings(base, head)
+
+	return change
+}
+
+// changedStepSettings returns, sorted, the steps whose typed ui_flow_settings differ
+func changedStepSettings(base, head *config.LenderConfig) []string {
+	baseSteps := typedSteps(base)
+	headSteps := typedSteps(head)
+
+	var changed []string
+	for name, settings := range headSteps {
+		if previous, ok := baseSteps[name]; !ok || !reflect.DeepEqual(previous, settings) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range baseSteps {
+		if _, ok := headSteps[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// typedSteps returns the per-step ui_flow_settings of a config; undecodable settings count as none
+func typedSteps(cfg *config.LenderConfig) map[string]config.StepSettings {
+	settings, _, err := cfg.TypedUIFlowSettings()
+	if err != nil || settings == nil {
+		return nil
+	}
+	return settings.Steps
+}
+
+func (c ConfigChange) isEmpty() bool {
+	return c.NameChange == nil && c.UIVersion == nil && len(c.UIFlow) == 0 &&
+		len(c.TagsAdded) == 0 && len(c.TagsRemoved) == 0 && c.Weight == nil && c.ABGroup == nil &&
+		len(c.StepSettings) == 0
+}
+
+// subtractTags returns the tags of a missing from b, counting duplicates
+func subtractTags(a, b []config.Tag) []config.Tag {
+	remaining := make(map[config.Tag]int)
+	for _, tag := range b {
+		remaining[tag]++
+	}
+
+	var result []config.Tag
+	for _, tag := range a {
+		if remaining[tag] > 0 {
+			remaining[tag]--
+			continue
+		}
+		result = append(result, tag)
+	}
+	return result
+}
+
+// groupMemberships maps each config ID to the A/B group it belongs to
+func groupMemberships(groups []ABTestingGroup) map[int]ABGroupMembership {
+	memberships := make(map[int]ABGroupMembership)
+	for _, group := range groups {
+		var ids []int
+		for _, variant := range group.Variants {
+			ids = append(ids, variant.ConfigID)
+		}
+		sort.Ints(ids)
+
+		for _, id := range ids {
+			memberships[id] = ABGroupMembership{Group: group.GroupName, Variants: ids}
+		}
+	}
+	return memberships
+}
+
+func sameMembership(a, b ABGroupMembership) bool {
+	if a.Group != b.Group || len(a.Variants) != len(b.Variants) {
+		return false
+	}
+	for i := range a.Variants {
+		if a.Variants[i] != b.Variants[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// configsByID indexes configs by ID; the first config wins when IDs are duplicated
+func configsByID(configs []*config.LenderConfig) map[int]*config.LenderConfig {
+	byID := make(map[int]*config.LenderConfig)
+	for _, cfg := range configs {
+		if _, ok := byID[cfg.ID]; !ok {
+			byID[cfg.ID] = cfg
+		}
+	}
+	return byID
+}
+
+func sortedConfigIDs(byID map[int]*config.LenderConfig) []int {
+	ids := make([]int, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func summarizeConfig(cfg *config.LenderConfig) ConfigSummary {
+	return ConfigSummary{
+		ConfigID:  cfg.ID,
+		Name:      cfg.Name,
+		UIVersion: cfg.UIVersion,
+		Weight:    cfg.Weight,
+		File:      cfg.SourcePath,
+	}
+}
@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func TestDiffConfigSnapshots(t *testing.T) {
+	tags := []config.Tag{{Name: "lead_source", Value: "organic"}, {Name: "flow_type", Value: "collect"}}
+
+	base := []*config.LenderConfig{
+		{ID: 1, Name: "collect", UIVersion: "v9.1.4.0", Weight: 100, Tags: tags, UIFlow: []string{"otp", "esign.review"}},
+		{ID: 2, Name: "cif", UIVersion: "v9.1.4.0", Weight: 100, UIFlow: []string{"cif.confirm"}},
+		{ID: 3, Name: "old", UIVersion: "v9.1.0.0", Weight: 100},
+	}
+	head := []*config.LenderConfig{
+		{ID: 1, Name: "collect", UIVersion: "v9.1.5.0", Weight: 50, Tags: append(append([]config.Tag{}, tags...), config.Tag{Name: "telco_code", Value: "viettel"}), UIFlow: []string{"consent", "otp", "esign.review"}},
+		{ID: 2, Name: "cif", UIVersion: "v9.1.4.0", Weight: 100, UIFlow: []string{"cif.confirm"}},
+		{ID: 4, Name: "collect", UIVersion: "v9.1.5.0", Weight: 50, Tags: append(append([]config.Tag{}, tags...), config.Tag{Name: "telco_code", Value: "viettel"}), UIFlow: []string{"otp", "ekyc.nfc", "esign.review"}},
+	}
+
+	diff := DiffConfigSnapshots(base, head, nil)
+
+	if len(diff.Added) != 1 || diff.Added[0].ConfigID != 4 {
+		t.Errorf("expected config 4 added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ConfigID != 3 {
+		t.Errorf("expected config 3 removed, got %+v", diff.Removed)
+	}
+	if diff.Unchanged != 1 {
+		t.Errorf("expected 1 unchanged config, got %d", diff.Unchanged)
+	}
+	if len(diff.Changed) != 1 {
+		t.Fatalf("expected 1 changed config, got %+v", diff.Changed)
+	}
+
+	change := diff.Changed[0]
+	if change.UIVersion == nil || change.UIVersion.To != "v9.1.5.0" {
+		t.Errorf("expected ui_version change, got %+v", change.UIVersion)
+	}
+	if len(change.UIFlow) != 1 || change.UIFlow[0].Op != FlowDiffInsert || change.UIFlow[0].Step != "consent" {
+		t.Errorf("expected consent inserted, got %+v", change.UIFlow)
+	}
+	if len(change.TagsAdded) != 1 || change.TagsAdded[0].Value != "viettel" || len(change.TagsRemoved) != 0 {
+		t.Errorf("expected telco_code added, got +%v -%v", change.TagsAdded, change.TagsRemoved)
+	}
+	if change.Weight == nil || change.Weight.From != 100 || change.Weight.To != 50 {
+		t.Errorf("expected weight change, got %+v", change.Weight)
+	}
+	if change.ABGroup == nil || change.ABGroup.From.Group != "" || len(change.ABGroup.To.Variants) != 2 {
+		t.Errorf("expected config 1 to join an A/B group, got %+v", change.ABGroup)
+	}
+
+	text := diff.String()
+	for _, want := range []string{"+ 4 collect", "- 3 old", "ui_flow: + consent (#1)", "A/B group: none -> collect (1, 4)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
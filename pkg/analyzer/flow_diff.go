@@ -0,0 +1,201 @@
+package analyzer
+
+import "fmt"
+
+// FlowDiffOp is the kind of a UI flow edit operation
+type FlowDiffOp string
+
+// UI flow edit operations
+const (
+	FlowDiffInsert  FlowDiffOp = "insert"
+	FlowDiffDelete  FlowDiffOp = "delete"
+	FlowDiffReplace FlowDiffOp = "replace"
+	FlowDiffMove    FlowDiffOp = "move"
+)
+
+// FlowDiffOperation is one edit turning the base UI flow into the variant UI flow.
+// Indices are 0-based; BaseIndex is -1 for inserts and VariantIndex is -1 for deletes.
+type FlowDiffOperation struct {
+	Op           FlowDiffOp `json:"op"`
+	Step         string     `json:"step"`
+	NewStep      string     `json:"new_step,omitempty"`
+	BaseIndex    int        `json:"base_index"`
+	VariantIndex int        `json:"variant_index"`
+}
+
+// String renders the operation with 1-based step numbers
+func (o FlowDiffOperation) String() string {
+	switch o.Op {
+	case FlowDiffInsert:
+		return fmt.Sprintf("Step %d: %s (extra in variant)", o.VariantIndex+1, o.Step)
+	case FlowDiffDelete:
+		return fmt.Sprintf("Step %d: %s (missing in variant)", o.BaseIndex+1, o.Step)
+	case FlowDiffReplace:
+		return fmt.Sprintf("Step %d: %s vs %s", o.BaseIndex+1, o.Step, o.NewStep)
+	case FlowDiffMove:
+		return fmt.Sprintf("Step %d: %s (moved to step %d in variant)", o.BaseIndex+1, o.Step, o.VariantIndex+1)
+	default:
+		return fmt.Sprintf("Step %d: %s (%s)", o.BaseIndex+1, o.Step, o.Op)
+	}
+}
+
+// Compact renders the operation for diagram labels and change lists: "+" inserted,
+// "-" removed, "~" replaced and ">" moved step, with 1-based step numbers
+func (o FlowDiffOperation) Compact() string {
+	switch o.Op {
+	case FlowDiffInsert:
+		return fmt.Sprintf("+ %s (#%d)", o.Step, o.VariantIndex+1)
+	case FlowDiffDelete:
+		return fmt.Sprintf("- %s (#%d)", o.Step, o.BaseIndex+1)
+	case FlowDiffReplace:
+		return fmt.Sprintf("~ %s -> %s (#%d)", o.Step, o.NewStep, o.BaseIndex+1)
+	case FlowDiffMove:
+		return fmt.Sprintf("> %s (#%d -> #%d)", o.Step, o.BaseIndex+1, o.VariantIndex+1)
+	default:
+		return o.String()
+	}
+}
+
+// DiffUIFlows computes the edit operations turning base into variant.
+//
+// Steps kept in order are found with a longest common subsequence. A step removed at one
+// place and added at another is reported as a move; remaining removals and additions
+// between the same kept steps are paired in order as replacements.
+func DiffUIFlows(base, variant []string) []FlowDiffOperation {
+	// lcs[i][j] is the LCS length of base[i:] and variant[j:]
+	lcs := make([][]int, len(base)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(variant)+1)
+	}
+	for i := len(base) - 1; i >= 0; i-- {
+		for j := len(variant) - 1; j >= 0; j-- {
+			if base[i] == variant[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// Split the edit script into hunks of deletions and insertions between kept steps
+	type hunk struct {
+		deleted  []int
+		inserted []int
+	}
+	var hunks []hunk
+	current := hunk{}
+	flush := func() {
+		if len(current.deleted) > 0 || len(current.inserted) > 0 {
+			hunks = append(hunks, current)
+		}
+		current = hunk{}
+	}
+
+	i, j := 0, 0
+	for i < len(base) || j < len(variant) {
+		switch {
+		case i < len(base) && j < len(variant) && base[i] == variant[j]:
+			flush()
+			i++
+			j++
+		case j < len(variant) && (i == len(base) || lcs[i][j+1] >= lcs[i+1][j]):
+			current.inserted = append(current.inserted, j)
+			j++
+		default:
+			current.deleted = append(current.deleted, i)
+			i++
+		}
+	}
+	flush()
+
+	// A step deleted in one place and inserted in another is a move
+	moveTo := make(map[int]int)
+	movedIn := make(map[int]bool)
+	for _, h := range hunks {
+		for _, d := range h.deleted {
+			for _, other := range hunks {
+				for _, in := range other.inserted {
+					if !movedIn[in] && variant[in] == base[d] {
+						moveTo[d] = in
+						movedIn[in] = true
+						break
+					}
+				}
+				if _, ok := moveTo[d]; ok {
+					break
+				}
+			}
+		}
+	}
+
+	var operations []FlowDiffOperation
+	for _, h := range hunks {
+		var deleted, inserted []int
+		for _, d := range h.deleted {
+			if to, ok := moveTo[d]; ok {
+				operations = append(operations, FlowDiffOperation{
+					Op: FlowDiffMove, Step: base[d], BaseIndex: d, VariantIndex: to,
+				})
+				continue
+			}
+			deleted = append(deleted, d)
+		}
+		for _, in := range h.inserted {
+			if !movedIn[in] {
+				inserted = append(inserted, in)
+			}
+		}
+
+		paired := min(len(deleted), len(inserted))
+		for k := 0; k < paired; k++ {
+			operations = append(operations, FlowDiffOperation{
+				Op: FlowDiffReplace, Step: base[deleted[k]], NewStep: variant[inserted[k]],
+				BaseIndex: deleted[k], VariantIndex: inserted[k],
+			})
+		}
+		for _, d := range deleted[paired:] {
+			operations = append(operations, FlowDiffOperation{
+				Op: FlowDiffDelete, Step: base[d], BaseIndex: d, VariantIndex: -1,
+			})
+		}
+		for _, in := range inserted[paired:] {
+			operations = append(operations, FlowDiffOperation{
+				Op: FlowDiffInsert, Step: variant[in], BaseIndex: -1, VariantIndex: in,
+			})
+		}
+	}
+
+	return operations
+}
+
+// FlowDiffStrings renders operations as human readable differences
+func FlowDiffStrings(operations []FlowDiffOperation) []string {
+	var differences []string
+	for _, op := range operations {
+		differences = append(differences, op.String())
+	}
+	return differences
+}
+
+// FlowDiffSummary counts operations by kind, e.g. "1 insert, 2 replace"
+func FlowDiffSummary(operations []FlowDiffOperation) string {
+	counts := make(map[FlowDiffOp]int)
+	for _, op := range operations {
+		counts[op.Op]++
+	}
+
+	summary := ""
+	for _, kind := range []FlowDiffOp{FlowDiffInsert, FlowDiffDelete, FlowDiffReplace, FlowDiffMove} {
+		if counts[kind] == 0 {
+			continue
+		}
+		if summary != "" {
+			summary += ", "
+		}
+		summary += fmt.Sprintf("%d %s", counts[kind], kind)
+	}
+	if summary == "" {
+		return "identical"
+	}
+	return summary
+}
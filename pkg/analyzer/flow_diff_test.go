@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffUIFlows(t *testing.T) {
+	base := []string{"otp", "app_form.basic_info", "ekyc.selfie.active", "inform.success", "esign.review"}
+
+	tests := []struct {
+		name    string
+		variant []string
+		want    []FlowDiffOperation
+	}{
+		{
+			name:    "identical",
+			variant: base,
+			want:    nil,
+		},
+		{
+			name:    "insert at front",
+			variant: append([]string{"consent"}, base...),
+			want: []FlowDiffOperation{
+				{Op: FlowDiffInsert, Step: "consent", BaseIndex: -1, VariantIndex: 0},
+			},
+		},
+		{
+			name:    "delete",
+			variant: []string{"otp", "app_form.basic_info", "inform.success", "esign.review"},
+			want: []FlowDiffOperation{
+				{Op: FlowDiffDelete, Step: "ekyc.selfie.active", BaseIndex: 2, VariantIndex: -1},
+			},
+		},
+		{
+			name:    "replace",
+			variant: []string{"otp", "app_form.basic_info", "ekyc.selfie.flash", "inform.success", "esign.review"},
+			want: []FlowDiffOperation{
+				{Op: FlowDiffReplace, Step: "ekyc.selfie.active", NewStep: "ekyc.selfie.flash", BaseIndex: 2, VariantIndex: 2},
+			},
+		},
+		{
+			name:    "move",
+			variant: []string{"otp", "ekyc.selfie.active", "app_form.basic_info", "inform.success", "esign.review"},
+			want: []FlowDiffOperation{
+				{Op: FlowDiffMove, Step: "ekyc.selfie.active", BaseIndex: 2, VariantIndex: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got := DiffUIFlows(base, tt.variant)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	// Inserting one step no longer reports every following step as changed
+	differences := FindUIFlowDifferences(base, append([]string{"consent"}, base...))
+	if want := []string{"Step 1: consent (extra in variant)"}; !reflect.DeepEqual(differences, want) {
+		t.Errorf("got %v, want %v", differences, want)
+	}
+}
@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// Grouping strategy names accepted by ParseGroupingStrategy
+const (
+	GroupingByName       = "name"
+	GroupingByTags       = "tags"
+	GroupingByExperiment = "experiment"
+)
+
+// ExperimentTagName is the tag explicitly naming the experiment a config belongs to
+const ExperimentTagName = "experiment"
+
+// DefaultGroupingSpec groups configs that have the same name and the same critical tags
+const DefaultGroupingSpec = GroupingByName + "+" + GroupingByTags
+
+// GroupingStrategy decides whether two configs belong to the same A/B testing group.
+// Match returns the name of the rule that grouped them, which is reported on the group.
+type GroupingStrategy interface {
+	Name() string
+	Match(config1, config2 *config.LenderConfig) (string, bool)
+}
+
+// NameStrategy groups configs with the same name
+type NameStrategy struct{}
+
+func (NameStrategy) Name() string { return GroupingByName }
+
+func (s NameStrategy) Match(config1, config2 *config.LenderConfig) (string, bool) {
+	return s.Name(), config1.Name == config2.Name
+}
+
+// TagSignatureStrategy groups configs with the same critical tags (see HasSameBasicTags)
+type TagSignatureStrategy struct{}
+
+func (TagSignatureStrategy) Name() string { return GroupingByTags }
+
+func (s TagSignatureStrategy) Match(config1, config2 *config.LenderConfig) (string, bool) {
+	return s.Name(), HasSameBasicTags(config1, config2)
+}
+
+// ExperimentTagStrategy groups configs declaring the same value for an experiment tag
+type ExperimentTagStrategy struct {
+	TagName string
+}
+
+func (s ExperimentTagStrategy) Name() string { return GroupingByExperiment }
+
+func (s ExperimentTagStrategy) Match(config1, config2 *config.LenderConfig) (string, bool) {
+	tagName := s.TagName
+	if tagName == "" {
+		tagName = ExperimentTagName
+	}
+
+	experiment1 := tagValue(config1.Tags, tagName)
+	return s.Name(), experiment1 != "" && experiment1 == tagValue(config2.Tags, tagName)
+}
+
+// AllOf groups configs matched by every strategy
+type AllOf []GroupingStrategy
+
+func (s AllOf) Name() string { return joinStrategyNames(s, "+") }
+
+func (s AllOf) Match(config1, config2 *config.LenderConfig) (string, bool) {
+	for _, strategy := range s {
+		if _, ok := strategy.Match(config1, config2); !ok {
+			return s.Name(), false
+		}
+	}
+	return s.Name(), len(s) > 0
+}
+
+// AnyOf groups configs matched by at least one strategy, reporting the first that matched
+type AnyOf []GroupingStrategy
+
+func (s AnyOf) Name() string { return joinStrategyNames(s, ",") }
+
+func (s AnyOf) Match(config1, config2 *config.LenderConfig) (string, bool) {
+	for _, strategy := range s {
+		if matched, ok := strategy.Match(config1, config2); ok {
+			return matched, true
+		}
+	}
+	return s.Name(), false
+}
+
+// DefaultGroupingStrategy returns the historical grouping: same name and same critical tags
+func DefaultGroupingStrategy() GroupingStrategy {
+	return AllOf{NameStrategy{}, TagSignatureStrategy{}}
+}
+
+// ParseGroupingStrategy builds a strategy from a spec such as "name+tags" or
+// "experiment,tags": "+" requires all strategies to match, "," tries alternatives in order
+// and binds looser than "+". An empty spec returns the default strategy.
+func ParseGroupingStrategy(spec string) (GroupingStrategy, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return DefaultGroupingStrategy(), nil
+	}
+
+	var alternatives AnyOf
+	for _, alternative := range strings.Split(spec, ",") {
+		var all AllOf
+		for _, name := range strings.Split(alternative, "+") {
+			switch strings.TrimSpace(name) {
+			case GroupingByName:
+				all = append(all, NameStrategy{})
+			case GroupingByTags:
+				all = append(all, TagSignatureStrategy{})
+			case GroupingByExperiment:
+				all = append(all, ExperimentTagStrategy{TagName: ExperimentTagName})
+			default:
+				return nil, fmt.Errorf("unknown A/B grouping strategy %q in %q (expected %s, %s or %s)",
+					strings.TrimSpace(name), spec, GroupingByName, GroupingByTags, GroupingByExperiment)
+			}
+		}
+
+		if len(all) == 1 {
+			alternatives = append(alternatives, all[0])
+		} else {
+			alternatives = append(alternatives, all)
+		}
+	}
+
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+	return alternatives, nil
+}
+
+// joinStrategyNames renders combined strategy names with sep
+func joinStrategyNames(strategies []GroupingStrategy, sep string) string {
+	names := make([]string, 0, len(strategies))
+	for _, strategy := range strategies {
+		names = append(names, strategy.Name())
+	}
+	return strings.Join(names, sep)
+}
+
+// tagValue returns the first value of a tag, or "" when the tag is absent
+func tagValue(tags []config.Tag, name string) string {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return tag.Value
+		}
+	}
+	return ""
+}
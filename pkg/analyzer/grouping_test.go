@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func TestGroupingStrategies(t *testing.T) {
+	basicTags := []config.Tag{
+		{Name: "product_code", Value: "evo"},
+		{Name: "lead_source", Value: "organic"},
+		{Name: "flow_type", Value: "collect"},
+	}
+	withExperiment := func(experiment string) []config.Tag {
+		return append(append([]config.Tag{}, basicTags...), config.Tag{Name: ExperimentTagName, Value: experiment})
+	}
+
+	configs := []*config.LenderConfig{
+		{ID: 1, Name: "v1.0.collect.organic", Weight: 50, UIFlow: []string{"otp", "esign.review"}, Tags: withExperiment("nfc")},
+		// Renamed variant of the same experiment
+		{ID: 2, Name: "v1.0.collect.organic.b", Weight: 50, UIFlow: []string{"otp", "ekyc.nfc", "esign.review"}, Tags: withExperiment("nfc")},
+		// Same name but a different product: not a variant by tag signature
+		{ID: 3, Name: "v1.0.collect.organic", Weight: 50, UIFlow: []string{"otp"},
+			Tags: []config.Tag{{Name: "product_code", Value: "cc"}, {Name: "lead_source", Value: "organic"}, {Name: "flow_type", Value: "collect"}}},
+	}
+
+	tests := []struct {
+		spec     string
+		groups   int
+		variants int
+		strategy string
+	}{
+		{"", 0, 0, ""},
+		{"name", 1, 2, "name"},
+		{"tags", 1, 2, "tags"},
+		{"experiment", 1, 2, "experiment"},
+		{"name+tags,experiment", 1, 2, "experiment"},
+	}
+
+	for _, tt := range tests {
+		strategy, err := ParseGroupingStrategy(tt.spec)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.spec, err)
+		}
+
+		groups := FindAllABTestingGroupsWith(strategy, configs)
+		if len(groups) != tt.groups {
+			t.Errorf("%q: expected %d groups, got %+v", tt.spec, tt.groups, groups)
+			continue
+		}
+		if tt.groups == 0 {
+			continue
+		}
+		if len(groups[0].Variants) != tt.variants || groups[0].Strategy != tt.strategy {
+			t.Errorf("%q: expected %d variants matched by %s, got %d by %s",
+				tt.spec, tt.variants, tt.strategy, len(groups[0].Variants), groups[0].Strategy)
+		}
+	}
+
+	if _, err := ParseGroupingStrategy("name+flow"); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
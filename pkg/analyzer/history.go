@@ -0,0 +1,239 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// Config history events
+const (
+	HistoryAdded   = "added"
+	HistoryRemoved = "removed"
+	HistoryChanged = "changed"
+)
+
+// ConfigRevision is a config as of a commit; Config is nil when the config does not exist at the commit
+type ConfigRevision struct {
+	Commit config.GitCommit
+	Config *config.LenderConfig
+}
+
+// HistoryEntry is a commit that added, removed or changed a config
+type HistoryEntry struct {
+	Commit config.GitCommit `json:"commit"`
+	Event  string           `json:"event"`
+	Change *ConfigChange    `json:"change,omitempty"`
+}
+
+// ConfigHistory is the change timeline of a config, oldest first
+type ConfigHistory struct {
+	ConfigID   int            `json:"config_id"`
+	LeadSource string         `json:"lead_source"`
+	Entries    []HistoryEntry `json:"entries"`
+}
+
+// StepBlame attributes a ui_flow step to the commits behind its current state
+type StepBlame struct {
+	Index int    `json:"index"`
+	Step  string `json:"step"`
+	// Commit is the commit that put the step at its position in ui_flow
+	Commit config.GitCommit `json:"commit"`
+	// SettingsCommit is the last commit that changed the step's ui_flow_settings, if any
+	SettingsCommit *config.GitCommit `json:"settings_commit,omitempty"`
+}
+
+// ConfigBlame attributes every step of a config's current ui_flow
+type ConfigBlame struct {
+	ConfigID   int         `json:"config_id"`
+	LeadSource string      `json:"lead_source"`
+	UIVersion  string      `json:"ui_version"`
+	Steps      []StepBlame `json:"steps"`
+}
+
+// LoadConfigRevisions loads a config at every commit that touched its file, oldest first
+func LoadConfigRevisions(ctx context.Context, provider *config.GitConfigProvider, configID int, leadSource string) ([]ConfigRevision, error) {
+	commits, err := provider.ConfigCommits(ctx, configID)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []ConfigRevision
+	for _, commit := range commits {
+		// A missing config at a commit means it was removed or not added yet
+		cfg, err := provider.AtCommit(commit).LoadConfig(ctx, configID, leadSource)
+		if err != nil {
+			cfg = nil
+		}
+		revisions = append(revisions, ConfigRevision{Commit: commit, Config: cfg})
+	}
+	return revisions, nil
+}
+
+// BuildConfigHistory lists the revisions where the config was added, removed, or had its
+// ui_version, ui_flow, tags, weight or step ui_flow_settings changed
+func BuildConfigHistory(configID int, leadSource string, revisions []ConfigRevision) *ConfigHistory {
+	history := &ConfigHistory{
+		ConfigID:   configID,
+		LeadSource: leadSource,
+		Entries:    []HistoryEntry{},
+	}
+
+	var previous *config.LenderConfig
+	for _, revision := range revisions {
+		current := revision.Config
+		switch {
+		case previous == nil && current != nil:
+			history.Entries = append(history.Entries, HistoryEntry{Commit: revision.Commit, Event: HistoryAdded})
+		case previous != nil && current == nil:
+			history.Entries = append(history.Entries, HistoryEntry{Commit: revision.Commit, Event: HistoryRemoved})
+		case previous != nil && current != nil:
+			change := diffConfig(previous, current)
+			if !change.isEmpty() {
+				history.Entries = append(history.Entries, HistoryEntry{Commit: revision.Commit, Event: HistoryChanged, Change: &change})
+			}
+		}
+		previous = current
+	}
+
+	return history
+}
+
+// BuildConfigBlame attributes every step of the config's latest ui_flow to the revision
+// that put it at its position: steps kept in order (see DiffUIFlows) keep their commit,
+// inserted, replaced and moved steps get the revision's commit. Re-adding a removed config
+// starts over.
+func BuildConfigBlame(configID int, leadSource string, revisions []ConfigRevision) (*ConfigBlame, error) {
+	var current *config.LenderConfig
+	var steps []StepBlame
+	settingsCommits := make(map[string]config.GitCommit)
+
+	for _, revision := range revisions {
+		cfg := revision.Config
+		if cfg == nil {
+			current = nil
+			steps = nil
+			settingsCommits = make(map[string]config.GitCommit)
+			continue
+		}
+
+		var previousFlow []string
+		if current != nil {
+			previousFlow = current.UIFlow
+		}
+		steps = blameFlow(previousFlow, cfg.UIFlow, steps, revision.Commit)
+
+		base := current
+		if base == nil {
+			base = &config.LenderConfig{}
+		}
+		for _, step := range changedStepSettings(base, cfg) {
+			settingsCommits[step] = revision.Commit
+		}
+
+		current = cfg
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("config %d does not exist at the last revision", configID)
+	}
+
+	for i := range steps {
+		if commit, ok := settingsCommits[steps[i].Step]; ok {
+			steps[i].SettingsCommit = &commit
+		}
+	}
+
+	return &ConfigBlame{
+		ConfigID:   configID,
+		LeadSource: leadSource,
+		UIVersion:  current.UIVersion,
+		Steps:      steps,
+	}, nil
+}
+
+// blameFlow carries the attributions of the previous flow over to the new flow
+func blameFlow(previousFlow, flow []string, previous []StepBlame, commit config.GitCommit) []StepBlame {
+	touchedBase := make(map[int]bool)
+	touchedVariant := make(map[int]bool)
+	for _, op := range DiffUIFlows(previousFlow, flow) {
+		if op.BaseIndex >= 0 {
+			touchedBase[op.BaseIndex] = true
+		}
+		if op.VariantIndex >= 0 {
+			touchedVariant[op.VariantIndex] = true
+		}
+	}
+
+	// The untouched steps form the common subsequence, so they pair up in order
+	var kept []StepBlame
+	for i := range previousFlow {
+		if !touchedBase[i] && i < len(previous) {
+			kept = append(kept, previous[i])
+		}
+	}
+
+	steps := make([]StepBlame, 0, len(flow))
+	for i, step := range flow {
+		blame := StepBlame{Index: i, Step: step, Commit: commit}
+		if !touchedVariant[i] && len(kept) > 0 {
+			blame.Commit = kept[0].Commit
+			kept = kept[1:]
+		}
+		steps = append(steps, blame)
+	}
+	return steps
+}
+
+// String renders the history for terminal output
+func (h *ConfigHistory) String() string {
+	var out strings.Builder
+
+	out.WriteString(fmt.Sprintf("History of config %d (%d entries)\n", h.ConfigID, len(h.Entries)))
+	for _, entry := range h.Entries {
+		out.WriteString(fmt.Sprintf("%s %s %s: %s (%s)\n",
+			entry.Commit.ShortHash(), entry.Commit.Date.Format("2006-01-02"), entry.Event, entry.Commit.Subject, entry.Commit.Author))
+		if entry.Change == nil {
+			continue
+		}
+
+		c := entry.Change
+		if c.UIVersion != nil {
+			out.WriteString(fmt.Sprintf("    ui_version: %s -> %s\n", c.UIVersion.From, c.UIVersion.To))
+		}
+		for _, op := range c.UIFlow {
+			out.WriteString(fmt.Sprintf("    ui_flow: %s\n", op.Compact()))
+		}
+		for _, tag := range c.TagsAdded {
+			out.WriteString(fmt.Sprintf("    tag added: %s=%s\n", tag.Name, tag.Value))
+		}
+		for _, tag := range c.TagsRemoved {
+			out.WriteString(fmt.Sprintf("    tag removed: %s=%s\n", tag.Name, tag.Value))
+		}
+		if c.Weight != nil {
+			out.WriteString(fmt.Sprintf("    weight: %d -> %d\n", c.Weight.From, c.Weight.To))
+		}
+		for _, step := range c.StepSettings {
+			out.WriteString(fmt.Sprintf("    ui_flow_settings: %s\n", step))
+		}
+	}
+	return out.String()
+}
+
+// String renders the blame for terminal output
+func (b *ConfigBlame) String() string {
+	var out strings.Builder
+
+	out.WriteString(fmt.Sprintf("Blame of config %d (ui_version %s)\n", b.ConfigID, b.UIVersion))
+	for _, step := range b.Steps {
+		out.WriteString(fmt.Sprintf("%2d. %-30s %s %s %s\n",
+			step.Index+1, step.Step, step.Commit.ShortHash(), step.Commit.Date.Format("2006-01-02"), step.Commit.Subject))
+		if step.SettingsCommit != nil && step.SettingsCommit.Hash != step.Commit.Hash {
+			out.WriteString(fmt.Sprintf("    settings: %s %s %s\n",
+				step.SettingsCommit.ShortHash(), step.SettingsCommit.Date.Format("2006-01-02"), step.SettingsCommit.Subject))
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func TestConfigHistoryAndBlame(t *testing.T) {
+	commit := func(hash, subject string) config.GitCommit {
+		return config.GitCommit{Hash: hash, Subject: subject}
+	}
+	revision := func(c config.GitCommit, cfg *config.LenderConfig) ConfigRevision {
+		return ConfigRevision{Commit: c, Config: cfg}
+	}
+
+	c1, c2, c3, c4, c5 := commit("c1", "add"), commit("c2", "touch"), commit("c3", "consent"), commit("c4", "nfc"), commit("c5", "selfie")
+
+	revisions := []ConfigRevision{
+		revision(commit("c0", "before"), nil),
+		revision(c1, &config.LenderConfig{ID: 9054, UIVersion: "v9.1.4.0", UIFlow: []string{"otp", "ekyc.selfie.active", "esign.review"}}),
+		// Formatting only: not part of the history
+		revision(c2, &config.LenderConfig{ID: 9054, UIVersion: "v9.1.4.0", UIFlow: []string{"otp", "ekyc.selfie.active", "esign.review"}}),
+		revision(c3, &config.LenderConfig{ID: 9054, UIVersion: "v9.1.5.0", UIFlow: []string{"consent", "otp", "ekyc.selfie.active", "esign.review"}}),
+		revision(c4, &config.LenderConfig{ID: 9054, UIVersion: "v9.1.5.0", UIFlow: []string{"consent", "otp", "ekyc.selfie.active", "esign.review"},
+			UIFlowSettings: map[string]interface{}{"esign.review": map[string]interface{}{"sub_ui_version": "v1.0-auto-nfc"}}}),
+		revision(c5, &config.LenderConfig{ID: 9054, UIVersion: "v9.1.5.0", UIFlow: []string{"consent", "otp", "ekyc.selfie.flash", "esign.review"},
+			UIFlowSettings: map[string]interface{}{"esign.review": map[string]interface{}{"sub_ui_version": "v1.0-auto-nfc"}}}),
+	}
+
+	history := BuildConfigHistory(9054, "organic", revisions)
+	events := []string{}
+	for _, entry := range history.Entries {
+		events = append(events, entry.Commit.Hash+":"+entry.Event)
+	}
+	want := []string{"c1:added", "c3:changed", "c4:changed", "c5:changed"}
+	if len(events) != len(want) {
+		t.Fatalf("expected %v, got %v", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, events)
+		}
+	}
+	if settings := history.Entries[2].Change.StepSettings; len(settings) != 1 || settings[0] != "esign.review" {
+		t.Errorf("expected esign.review settings change, got %v", settings)
+	}
+
+	blame, err := BuildConfigBlame(9054, "organic", revisions)
+	if err != nil {
+		t.Fatalf("BuildConfigBlame failed: %v", err)
+	}
+
+	wantCommits := []string{"c3", "c1", "c5", "c1"}
+	for i, step := range blame.Steps {
+		if step.Commit.Hash != wantCommits[i] {
+			t.Errorf("step %s: expected commit %s, got %s", step.Step, wantCommits[i], step.Commit.Hash)
+		}
+	}
+	if s := blame.Steps[3].SettingsCommit; s == nil || s.Hash != "c4" {
+		t.Errorf("expected esign.review settings from c4, got %+v", s)
+	}
+	if blame.Steps[0].SettingsCommit != nil {
+		t.Errorf("expected no settings commit for consent, got %+v", blame.Steps[0].SettingsCommit)
+	}
+
+	if _, err := BuildConfigBlame(9054, "organic", append(revisions, revision(commit("c6", "remove"), nil))); err == nil {
+		t.Error("expected an error when the config was removed")
+	}
+}
@@ -0,0 +1,215 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// RoutingCandidate is a config a user is eligible for
+type RoutingCandidate struct {
+	ConfigID    int          `json:"config_id"`
+	Name        string       `json:"name"`
+	FlowType    string       `json:"flow_type"`
+	UIVersion   string       `json:"ui_version"`
+	Weight      int          `json:"weight"`
+	Probability float64      `json:"probability"`
+	MatchedTags []config.Tag `json:"matched_tags"`
+	Score       int          `json:"score"`
+}
+
+// RoutingGroup is a set of weighted alternatives (A/B variants) grouped by the A/B grouping
+// strategy; a user landing on the group is sent to one of its candidates with the
+// candidate's Probability
+type RoutingGroup struct {
+	Name        string             `json:"name"`
+	Score       int                `json:"score"`
+	TotalWeight int                `json:"total_weight"`
+	Candidates  []RoutingCandidate `json:"candidates"`
+}
+
+// RoutingExclusion explains why a config is not eligible for the user
+type RoutingExclusion struct {
+	ConfigID int    `json:"config_id"`
+	Name     string `json:"name"`
+	Reason   string `json:"reason"`
+}
+
+// RoutingResult lists the eligible configs for a tag set, best group first
+type RoutingResult struct {
+	Tags     map[string]string  `json:"tags"`
+	Groups   []RoutingGroup     `json:"groups"`
+	Excluded []RoutingExclusion `json:"excluded"`
+}
+
+// Best returns the group the user most likely lands on, or nil when no config is eligible
+func (r *RoutingResult) Best() *RoutingGroup {
+	if len(r.Groups) == 0 {
+		return nil
+	}
+	return &r.Groups[0]
+}
+
+// ResolveRouting finds the configs a user with the given tags is eligible for.
+//
+// A config is eligible when, for every user tag it declares, one of its values equals the
+// user's value; tags the config does not declare do not restrict it. flow_type is compared
+// with the config's effective flow type (esign_flow_type first). Candidates are grouped into
+// weighted alternatives by strategy, as A/B testing groups are, and groups are ranked by
+// Score, the number of user tags the config matched explicitly, so more specific configs
+// come first.
+func ResolveRouting(strategy GroupingStrategy, configs []*config.LenderConfig, tags map[string]string) *RoutingResult {
+	result := &RoutingResult{
+		Tags:     tags,
+		Groups:   []RoutingGroup{},
+		Excluded: []RoutingExclusion{},
+	}
+
+	// Check tags in a stable order so exclusion reasons are deterministic
+	tagNames := make([]string, 0, len(tags))
+	for name := range tags {
+		tagNames = append(tagNames, name)
+	}
+	sort.Strings(tagNames)
+
+	var groupConfigs [][]*config.LenderConfig
+	var groupScores []int
+	scores := make(map[int]int)
+	matched := make(map[int][]config.Tag)
+
+	for _, cfg := range configs {
+		matchedTags, reason := matchRoutingTags(cfg, tags, tagNames)
+		if reason != "" {
+			result.Excluded = append(result.Excluded, RoutingExclusion{
+				ConfigID: cfg.ID,
+				Name:     cfg.Name,
+				Reason:   reason,
+			})
+			continue
+		}
+
+		scores[cfg.ID] = len(matchedTags)
+		matched[cfg.ID] = matchedTags
+
+		grouped := false
+		for i, group := range groupConfigs {
+			if _, ok := strategy.Match(group[0], cfg); ok {
+				groupConfigs[i] = append(groupConfigs[i], cfg)
+				if scores[cfg.ID] > groupScores[i] {
+					groupScores[i] = scores[cfg.ID]
+				}
+				grouped = true
+				break
+			}
+		}
+		if !grouped {
+			groupConfigs = append(groupConfigs, []*config.LenderConfig{cfg})
+			groupScores = append(groupScores, scores[cfg.ID])
+		}
+	}
+
+	for i, group := range groupConfigs {
+		routingGroup := RoutingGroup{
+			Name:  group[0].Name,
+			Score: groupScores[i],
+		}
+		for _, cfg := range group {
+			if cfg.Weight > 0 {
+				routingGroup.TotalWeight += cfg.Weight
+			}
+		}
+
+		for _, cfg := range group {
+			routingGroup.Candidates = append(routingGroup.Candidates, RoutingCandidate{
+				ConfigID:    cfg.ID,
+				Name:        cfg.Name,
+				FlowType:    config.GetFlowTypeFromTags(cfg.Tags),
+				UIVersion:   cfg.UIVersion,
+				Weight:      cfg.Weight,
+				Probability: trafficShare(cfg.Weight, routingGroup.TotalWeight, len(group)),
+				MatchedTags: matched[cfg.ID],
+				Score:       scores[cfg.ID],
+			})
+		}
+
+		sort.SliceStable(routingGroup.Candidates, func(a, b int) bool {
+			ca, cb := routingGroup.Candidates[a], routingGroup.Candidates[b]
+			if ca.Probability != cb.Probability {
+				return ca.Probability > cb.Probability
+			}
+			return ca.ConfigID < cb.ConfigID
+		})
+
+		result.Groups = append(result.Groups, routingGroup)
+	}
+
+	sort.SliceStable(result.Groups, func(a, b int) bool {
+		ga, gb := result.Groups[a], result.Groups[b]
+		if ga.Score != gb.Score {
+			return ga.Score > gb.Score
+		}
+		return ga.Candidates[0].ConfigID < gb.Candidates[0].ConfigID
+	})
+
+	sort.SliceStable(result.Excluded, func(a, b int) bool {
+		return result.Excluded[a].ConfigID < result.Excluded[b].ConfigID
+	})
+
+	return result
+}
+
+// matchRoutingTags returns the user tags matched by cfg, or the reason cfg is excluded
+func matchRoutingTags(cfg *config.LenderConfig, tags map[string]string, tagNames []string) ([]config.Tag, string) {
+	configTags := make(map[string][]string)
+	for _, tag := range cfg.Tags {
+		configTags[tag.Name] = append(configTags[tag.Name], tag.Value)
+	}
+
+	matched := []config.Tag{}
+	for _, name := range tagNames {
+		value := tags[name]
+		if value == "" {
+			continue
+		}
+
+		values := configTags[name]
+		if name == "flow_type" || name == "esign_flow_type" {
+			flowType := config.GetFlowTypeFromTags(cfg.Tags)
+			if flowType == "unknown" {
+				continue
+			}
+			values = []string{flowType}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		found := false
+		for _, v := range values {
+			if v == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Sprintf("%s=%s does not match %s", name, value, strings.Join(values, ", "))
+		}
+		matched = append(matched, config.Tag{Name: name, Value: value})
+	}
+
+	return matched, ""
+}
+
+// trafficShare returns the probability of a weighted alternative; when no alternative
+// has a positive weight traffic is split evenly
+func trafficShare(weight, totalWeight, count int) float64 {
+	if totalWeight <= 0 {
+		return 1 / float64(count)
+	}
+	if weight <= 0 {
+		return 0
+	}
+	return float64(weight) / float64(totalWeight)
+}
@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func TestResolveRouting(t *testing.T) {
+	tags := func(pairs ...string) []config.Tag {
+		var result []config.Tag
+		for i := 0; i < len(pairs); i += 2 {
+			result = append(result, config.Tag{Name: pairs[i], Value: pairs[i+1]})
+		}
+		return result
+	}
+
+	configs := []*config.LenderConfig{
+		{ID: 1, Name: "evo.auto", Weight: 70, UIFlow: []string{"otp", "esign.review"},
+			Tags: tags("product_code", "evo", "lead_source", "organic", "telco_code", "viettel", "flow_type", "auto")},
+		{ID: 2, Name: "evo.auto", Weight: 30, UIFlow: []string{"otp", "ekyc.nfc", "esign.review"},
+			Tags: tags("product_code", "evo", "lead_source", "organic", "telco_code", "viettel", "flow_type", "auto")},
+		{ID: 3, Name: "evo.generic", Weight: 100,
+			Tags: tags("product_code", "evo", "flow_type", "auto")},
+		{ID: 4, Name: "evo.paid", Weight: 100,
+			Tags: tags("product_code", "evo", "lead_source", "paid")},
+	}
+
+	result := ResolveRouting(DefaultGroupingStrategy(), configs, map[string]string{
+		"product_code": "evo",
+		"lead_source":  "organic",
+		"telco_code":   "viettel",
+	})
+
+	if len(result.Groups) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", result.Groups)
+	}
+
+	best := result.Best()
+	if best.Name != "evo.auto" || best.Score != 3 || best.TotalWeight != 100 {
+		t.Fatalf("unexpected best group: %+v", best)
+	}
+	if best.Candidates[0].ConfigID != 1 || math.Abs(best.Candidates[0].Probability-0.7) > 1e-9 {
+		t.Errorf("expected config 1 with 70%% traffic first, got %+v", best.Candidates[0])
+	}
+
+	// The generic config does not declare lead_source or telco_code, so it stays as a fallback
+	if result.Groups[1].Candidates[0].ConfigID != 3 || result.Groups[1].Score != 1 {
+		t.Errorf("expected generic config as fallback, got %+v", result.Groups[1])
+	}
+
+	if len(result.Excluded) != 1 || result.Excluded[0].ConfigID != 4 {
+		t.Fatalf("expected config 4 to be excluded, got %+v", result.Excluded)
+	}
+	if want := "lead_source=organic does not match paid"; result.Excluded[0].Reason != want {
+		t.Errorf("expected reason %q, got %q", want, result.Excluded[0].Reason)
+	}
+}
+
+func TestResolveRoutingGroupingStrategy(t *testing.T) {
+	experiment := func(id int, name, value string, weight int) *config.LenderConfig {
+		return &config.LenderConfig{ID: id, Name: name, Weight: weight,
+			Tags: []config.Tag{{Name: "product_code", Value: "evo"}, {Name: ExperimentTagName, Value: value}}}
+	}
+	// Differently named variants of one experiment
+	configs := []*config.LenderConfig{
+		experiment(1, "evo.auto.a", "exp-1", 50),
+		experiment(2, "evo.auto.b", "exp-1", 50),
+		experiment(3, "evo.semi", "exp-2", 100),
+	}
+	tags := map[string]string{"product_code": "evo"}
+
+	if result := ResolveRouting(DefaultGroupingStrategy(), configs, tags); len(result.Groups) != 3 {
+		t.Fatalf("expected one group per name with the default strategy, got %+v", result.Groups)
+	}
+
+	strategy, err := ParseGroupingStrategy(GroupingByExperiment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := ResolveRouting(strategy, configs, tags)
+	if len(result.Groups) != 2 {
+		t.Fatalf("expected the experiment strategy to form 2 groups, got %+v", result.Groups)
+	}
+	group := result.Groups[0]
+	if len(group.Candidates) != 2 || group.TotalWeight != 100 || math.Abs(group.Candidates[1].Probability-0.5) > 1e-9 {
+		t.Errorf("expected configs 1 and 2 to split traffic 50/50, got %+v", group)
+	}
+}
@@ -5,12 +5,23 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/tsocial/ui-version-mapping/pkg/condition"
 	"github.com/tsocial/ui-version-mapping/pkg/config"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+	"github.com/tsocial/ui-version-mapping/pkg/lint"
 )
 
 // AnalyzerService là service chính cho việc phân tích configs
 type AnalyzerService struct {
 	configProvider config.ConfigProvider
+	stepTemplates  *journey.StepTemplateSet
+	stepCatalogue  *journey.StepCatalogue
+	grouping       GroupingStrategy
+	loadMode       config.LoadMode
+	// Distinct load problems of all loads so far
+	loadDiagnostics []config.LoadDiagnostic
+	loadDuplicates  []config.DuplicateConfigID
+	seenLoadProblem map[string]bool
 }
 
 // NewAnalyzerService tạo analyzer service mới
@@ -20,6 +31,93 @@ func NewAnalyzerService(provider config.ConfigProvider) *AnalyzerService {
 	}
 }
 
+// SetStepTemplates thay thế step templates mặc định khi tạo journey
+func (s *AnalyzerService) SetStepTemplates(templates *journey.StepTemplateSet) {
+	s.stepTemplates = templates
+}
+
+// SetStepCatalogue thay thế step catalogue mặc định khi tạo journey
+func (s *AnalyzerService) SetStepCatalogue(catalogue *journey.StepCatalogue) {
+	s.stepCatalogue = catalogue
+}
+
+// StepCatalogue trả về step catalogue dùng khi tạo journey (catalogue built-in nếu chưa set)
+func (s *AnalyzerService) StepCatalogue() *journey.StepCatalogue {
+	if s.stepCatalogue == nil {
+		return journey.DefaultStepCatalogue()
+	}
+	return s.stepCatalogue
+}
+
+// SetGroupingStrategy thay thế strategy mặc định (cùng name và cùng critical tags) khi nhóm A/B testing variants
+func (s *AnalyzerService) SetGroupingStrategy(strategy GroupingStrategy) {
+	s.grouping = strategy
+}
+
+// SetLoadMode chọn cách xử lý config files không load được: lenient (mặc định) bỏ qua và
+// cảnh báo, strict trả về *config.LoadError
+func (s *AnalyzerService) SetLoadMode(mode config.LoadMode) {
+	s.loadMode = mode
+}
+
+// LoadDiagnostics trả về các config files không load được trong các lần load đã chạy
+func (s *AnalyzerService) LoadDiagnostics() []config.LoadDiagnostic {
+	return s.loadDiagnostics
+}
+
+// LoadDuplicates trả về các config IDs bị khai báo trong nhiều files trong các lần load đã chạy
+func (s *AnalyzerService) LoadDuplicates() []config.DuplicateConfigID {
+	return s.loadDuplicates
+}
+
+// loadConfigs load configs từ provider theo load mode; in lenient mode every failing file and
+// duplicated config ID is printed as a warning the first time it is seen
+func (s *AnalyzerService) loadConfigs(ctx context.Context, provider config.ConfigProvider, folderPath string) ([]*config.LenderConfig, error) {
+	mode := s.loadMode
+	if mode == "" {
+		mode = config.LoadLenient
+	}
+
+	configs, report, err := config.LoadConfigsWithReport(ctx, provider, folderPath, mode)
+	if report != nil {
+		if s.seenLoadProblem == nil {
+			s.seenLoadProblem = make(map[string]bool)
+		}
+		for _, d := range report.Diagnostics {
+			if s.seenLoadProblem[d.String()] {
+				continue
+			}
+			s.seenLoadProblem[d.String()] = true
+			s.loadDiagnostics = append(s.loadDiagnostics, d)
+			if mode == config.LoadLenient {
+				fmt.Printf("Warning: skipped config file %s\n", d)
+			}
+		}
+		for _, d := range report.Duplicates {
+			if s.seenLoadProblem[d.String()] {
+				continue
+			}
+			s.seenLoadProblem[d.String()] = true
+			s.loadDuplicates = append(s.loadDuplicates, d)
+			if mode == config.LoadLenient {
+				fmt.Printf("Warning: %s\n", d)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// groupingStrategy trả về strategy đang dùng để nhóm A/B testing variants
+func (s *AnalyzerService) groupingStrategy() GroupingStrategy {
+	if s.grouping == nil {
+		return DefaultGroupingStrategy()
+	}
+	return s.grouping
+}
+
 // SearchRelatedConfigs tìm các configs liên quan đến một config ID
 func (s *AnalyzerService) SearchRelatedConfigs(ctx context.Context, configID int, leadSource string, folderPath string) ([]config.RelatedConfigResult, error) {
 	// Load source config
@@ -29,18 +127,23 @@ func (s *AnalyzerService) SearchRelatedConfigs(ctx context.Context, configID int
 	}
 
 	// Load all configs from path
-	allConfigs, err := s.configProvider.LoadConfigs(ctx, folderPath)
+	allConfigs, err := s.loadConfigs(ctx, s.configProvider, folderPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configs from %s: %w", folderPath, err)
 	}
 
 	fmt.Printf("Found %d configs in %s\n", len(allConfigs), folderPath)
 
+	return s.findRelatedConfigs(sourceConfig, leadSource, allConfigs), nil
+}
+
+// findRelatedConfigs tìm trong allConfigs các A/B testing variants và các configs có tags tương thích với sourceConfig
+func (s *AnalyzerService) findRelatedConfigs(sourceConfig *config.LenderConfig, leadSource string, allConfigs []*config.LenderConfig) []config.RelatedConfigResult {
 	var results []config.RelatedConfigResult
 	resultMap := make(map[int]bool)
 
 	// Detect A/B testing variants first
-	abVariants := DetectABTestingVariants(sourceConfig, allConfigs)
+	abVariants, _ := DetectABTestingVariantsWith(s.groupingStrategy(), sourceConfig, allConfigs)
 	var abVariantIDs []int
 	for _, variant := range abVariants {
 		abVariantIDs = append(abVariantIDs, variant.ConfigID)
@@ -58,7 +161,7 @@ func (s *AnalyzerService) SearchRelatedConfigs(ctx context.Context, configID int
 	}
 
 	for _, cfg := range allConfigs {
-		if cfg.ID == configID || resultMap[cfg.ID] {
+		if cfg.ID == sourceConfig.ID || resultMap[cfg.ID] {
 			continue
 		}
 
@@ -110,17 +213,226 @@ func (s *AnalyzerService) SearchRelatedConfigs(ctx context.Context, configID int
 		}
 	}
 
-	return results, nil
+	return results
 }
 
 // FindABTestingGroups tìm tất cả A/B testing groups
 func (s *AnalyzerService) FindABTestingGroups(ctx context.Context, folderPath string) ([]ABTestingGroup, error) {
-	allConfigs, err := s.configProvider.LoadConfigs(ctx, folderPath)
+	allConfigs, err := s.loadConfigs(ctx, s.configProvider, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configs: %w", err)
+	}
+
+	return FindAllABTestingGroupsWith(s.groupingStrategy(), allConfigs), nil
+}
+
+// ConfigTopology dựng routing graph của tất cả configs trong folder: journeys giữa các configs
+// liên quan và A/B testing siblings
+func (s *AnalyzerService) ConfigTopology(ctx context.Context, folderPath string) (*ConfigTopology, error) {
+	allConfigs, err := s.loadConfigs(ctx, s.configProvider, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configs: %w", err)
+	}
+
+	groups := FindAllABTestingGroupsWith(s.groupingStrategy(), allConfigs)
+	related := func(source *config.LenderConfig, leadSource string) []config.RelatedConfigResult {
+		return s.findRelatedConfigs(source, leadSource, allConfigs)
+	}
+	return BuildConfigTopology(folderPath, allConfigs, groups, related), nil
+}
+
+// CheckABHealth kiểm tra traffic weights của các A/B testing groups trong folder
+func (s *AnalyzerService) CheckABHealth(ctx context.Context, folderPath string, expectedTotal int) (*ABHealthReport, error) {
+	allConfigs, err := s.loadConfigs(ctx, s.configProvider, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configs: %w", err)
+	}
+
+	return CheckABHealth(s.groupingStrategy(), allConfigs, expectedTotal), nil
+}
+
+// LintConfigs kiểm tra các configs trong folder với rule catalogue của linter
+func (s *AnalyzerService) LintConfigs(ctx context.Context, folderPath string, linter *lint.Linter) (*lint.Report, error) {
+	allConfigs, err := s.loadConfigs(ctx, s.configProvider, folderPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configs: %w", err)
 	}
 
-	return FindAllABTestingGroups(allConfigs), nil
+	return linter.Lint(allConfigs), nil
+}
+
+// SimulateTrafficSplit mô phỏng việc chia traffic cho các A/B testing groups chứa config ID
+func (s *AnalyzerService) SimulateTrafficSplit(ctx context.Context, configID int, folderPath string, opts TrafficSplitOptions) ([]*TrafficSplitResult, error) {
+	groups, err := s.FindABTestingGroups(ctx, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*TrafficSplitResult{}
+	for _, group := range groups {
+		inGroup := false
+		for _, variant := range group.Variants {
+			if variant.ConfigID == configID {
+				inGroup = true
+				break
+			}
+		}
+		if !inGroup {
+			continue
+		}
+
+		result, err := SimulateTrafficSplit(group, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to simulate traffic split of group %s: %w", group.GroupName, err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// DiffConfigs so sánh configs của base provider (snapshot cũ) với provider của service (snapshot mới)
+func (s *AnalyzerService) DiffConfigs(ctx context.Context, base config.ConfigProvider, folderPath, baseLabel, headLabel string) (*ConfigSnapshotDiff, error) {
+	baseConfigs, err := s.loadConfigs(ctx, base, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base configs from %s: %w", baseLabel, err)
+	}
+
+	headConfigs, err := s.loadConfigs(ctx, s.configProvider, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load head configs from %s: %w", headLabel, err)
+	}
+
+	diff := DiffConfigSnapshots(baseConfigs, headConfigs, s.groupingStrategy())
+	diff.Base = baseLabel
+	diff.Head = headLabel
+	return diff, nil
+}
+
+// ConfigHistory tạo timeline thay đổi của một config; yêu cầu git config provider
+func (s *AnalyzerService) ConfigHistory(ctx context.Context, configID int, leadSource string) (*ConfigHistory, error) {
+	revisions, err := s.configRevisions(ctx, configID, leadSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildConfigHistory(configID, leadSource, revisions), nil
+}
+
+// ConfigBlame tìm commit đã đưa từng step của ui_flow vào config; yêu cầu git config provider
+func (s *AnalyzerService) ConfigBlame(ctx context.Context, configID int, leadSource string) (*ConfigBlame, error) {
+	revisions, err := s.configRevisions(ctx, configID, leadSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildConfigBlame(configID, leadSource, revisions)
+}
+
+// configRevisions load các phiên bản của config qua git history
+func (s *AnalyzerService) configRevisions(ctx context.Context, configID int, leadSource string) ([]ConfigRevision, error) {
+	provider, ok := s.configProvider.(*config.GitConfigProvider)
+	if !ok {
+		return nil, fmt.Errorf("config history requires a git config provider")
+	}
+
+	revisions, err := LoadConfigRevisions(ctx, provider, configID, leadSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load revisions of config %d: %w", configID, err)
+	}
+	return revisions, nil
+}
+
+// AnalyzeABTesting tạo kết quả phân tích A/B testing cho một config ID
+func (s *AnalyzerService) AnalyzeABTesting(ctx context.Context, configID int, leadSource string, folderPath string) (*ABTestingAnalysisResult, error) {
+	groups, err := s.FindABTestingGroups(ctx, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	relatedConfigs, err := s.SearchRelatedConfigs(ctx, configID, leadSource, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Separate normal results from A/B testing variants
+	var normalResults []config.RelatedConfigResult
+	for _, result := range relatedConfigs {
+		if !result.IsABTesting {
+			normalResults = append(normalResults, result)
+		}
+	}
+
+	return &ABTestingAnalysisResult{
+		SearchID:        configID,
+		SearchType:      SearchTypeABTestingAnalysis,
+		ABTestingGroups: groups,
+		NormalResults:   normalResults,
+		TotalResults:    len(groups) + len(normalResults),
+	}, nil
+}
+
+// GenerateJourneyTemplate tạo journey template từ source config và các related configs
+func (s *AnalyzerService) GenerateJourneyTemplate(ctx context.Context, configID int, leadSource string, relatedConfigs []config.RelatedConfigResult) (*journey.JourneyTemplate, error) {
+	return journey.NewBuilder(s.configProvider).WithStepTemplates(s.stepTemplates).WithStepCatalogue(s.stepCatalogue).Build(ctx, configID, leadSource, relatedConfigs)
+}
+
+// ResolveRouting tìm các configs mà user với tags sẽ được route tới, kèm xác suất theo weight
+func (s *AnalyzerService) ResolveRouting(ctx context.Context, folderPath string, tags map[string]string) (*RoutingResult, error) {
+	allConfigs, err := s.loadConfigs(ctx, s.configProvider, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configs from %s: %w", folderPath, err)
+	}
+
+	return ResolveRouting(s.groupingStrategy(), allConfigs, tags), nil
+}
+
+// SimulateJourney tạo journey template cho config và mô phỏng journey cụ thể mà user với attrs sẽ đi qua
+func (s *AnalyzerService) SimulateJourney(ctx context.Context, configID int, leadSource string, folderPath string, attrs condition.Attributes) (*journey.Simulation, error) {
+	relatedConfigs, err := s.SearchRelatedConfigs(ctx, configID, leadSource, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := s.GenerateJourneyTemplate(ctx, configID, leadSource, relatedConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate journey template: %w", err)
+	}
+
+	return journey.Simulate(template, attrs)
+}
+
+// UIVersionMapping tạo journey template cho config và trải phẳng mọi step thành các dòng UI
+// version, kèm các A/B testing groups của folder
+func (s *AnalyzerService) UIVersionMapping(ctx context.Context, configID int, leadSource string, folderPath string) (*UIVersionMapping, error) {
+	relatedConfigs, err := s.SearchRelatedConfigs(ctx, configID, leadSource, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := s.GenerateJourneyTemplate(ctx, configID, leadSource, relatedConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate journey template: %w", err)
+	}
+
+	// Target configs supply weight, path and decision trees; the builder already warned
+	// about the ones it could not load
+	targets := make(map[int]*config.LenderConfig)
+	for _, j := range template.Journeys {
+		if _, ok := targets[j.ToLenderConfigID]; ok {
+			continue
+		}
+		if cfg, err := s.configProvider.LoadConfig(ctx, j.ToLenderConfigID, ""); err == nil {
+			targets[j.ToLenderConfigID] = cfg
+		}
+	}
+
+	groups, err := s.FindABTestingGroups(ctx, folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildUIVersionMapping(configID, leadSource, template, targets, groups), nil
 }
 
 // isCompatibleByTags kiểm tra tính tương thích của tags
@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+)
+
+// Kinds of topology edges
+const (
+	TopologyEdgeJourney   = "journey"
+	TopologyEdgeABSibling = "ab_sibling"
+)
+
+// Tags a topology can be clustered by
+const (
+	ClusterByLeadSource  = "lead_source"
+	ClusterByProductCode = "product_code"
+)
+
+// ConfigTopology is the routing graph of all configs of a folder
+type ConfigTopology struct {
+	Folder string         `json:"folder"`
+	Nodes  []TopologyNode `json:"nodes"`
+	Edges  []TopologyEdge `json:"edges"`
+}
+
+// TopologyNode is a lender config of the routing graph
+type TopologyNode struct {
+	ConfigID    int      `json:"config_id"`
+	Name        string   `json:"name"`
+	UIVersion   string   `json:"ui_version"`
+	FlowType    string   `json:"flow_type"`
+	Weight      int      `json:"weight"`
+	LeadSources []string `json:"lead_sources,omitempty"`
+	ProductCode string   `json:"product_code,omitempty"`
+	SourcePath  string   `json:"source_path,omitempty"`
+}
+
+// TopologyEdge links two configs. Journey edges point from a config to a related config
+// its users can be routed to, with the flow type of the journey. A/B sibling edges join
+// two variants of an A/B group and have no direction.
+type TopologyEdge struct {
+	From       int    `json:"from"`
+	To         int    `json:"to"`
+	Kind       string `json:"kind"`
+	FlowType   string `json:"flow_type,omitempty"`
+	Group      string `json:"group,omitempty"`
+	FromWeight int    `json:"from_weight"`
+	ToWeight   int    `json:"to_weight"`
+}
+
+// RelatedConfigsFunc finds the configs related to a source config for a lead source
+type RelatedConfigsFunc func(source *config.LenderConfig, leadSource string) []config.RelatedConfigResult
+
+// ParseClusterBy checks a cluster key; an empty key does not cluster
+func ParseClusterBy(key string) (string, error) {
+	switch key {
+	case "", ClusterByLeadSource, ClusterByProductCode:
+		return key, nil
+	default:
+		return "", fmt.Errorf("unknown cluster key %q (want %s or %s)", key, ClusterByLeadSource, ClusterByProductCode)
+	}
+}
+
+// ClusterValue returns the value of the cluster key for the node; configs with several
+// lead sources are clustered by the first one
+func (n TopologyNode) ClusterValue(key string) string {
+	switch key {
+	case ClusterByLeadSource:
+		if len(n.LeadSources) > 0 {
+			return n.LeadSources[0]
+		}
+	case ClusterByProductCode:
+		return n.ProductCode
+	}
+	return ""
+}
+
+// BuildConfigTopology builds the routing graph of configs. Every config gets a journey edge
+// to each non-A/B related config found by related for each of its lead sources, and the
+// variants of each A/B group are joined pairwise by sibling edges.
+func BuildConfigTopology(folder string, configs []*config.LenderConfig, groups []ABTestingGroup, related RelatedConfigsFunc) *ConfigTopology {
+	topology := &ConfigTopology{
+		Folder: folder,
+		Nodes:  []TopologyNode{},
+		Edges:  []TopologyEdge{},
+	}
+
+	sorted := append([]*config.LenderConfig(nil), configs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	byID := make(map[int]*config.LenderConfig, len(sorted))
+	for _, cfg := range sorted {
+		if _, ok := byID[cfg.ID]; ok {
+			continue // duplicated IDs are reported by the load report
+		}
+		byID[cfg.ID] = cfg
+		topology.Nodes = append(topology.Nodes, newTopologyNode(cfg))
+	}
+
+	for _, node := range topology.Nodes {
+		source := byID[node.ConfigID]
+		leadSources := node.LeadSources
+		if len(leadSources) == 0 {
+			leadSources = []string{""}
+		}
+
+		seen := make(map[int]bool)
+		for _, leadSource := range leadSources {
+			for _, result := range related(source, leadSource) {
+				target, ok := byID[result.ConfigID]
+				if result.IsABTesting || !ok || seen[result.ConfigID] {
+					continue
+				}
+				seen[result.ConfigID] = true
+				topology.Edges = append(topology.Edges, TopologyEdge{
+					From:       source.ID,
+					To:         target.ID,
+					Kind:       TopologyEdgeJourney,
+					FlowType:   journey.DetermineFlowType(source, target, result.MatchReason),
+					FromWeight: source.Weight,
+					ToWeight:   target.Weight,
+				})
+			}
+		}
+	}
+
+	for _, group := range groups {
+		for i, a := range group.Variants {
+			for _, b := range group.Variants[i+1:] {
+				topology.Edges = append(topology.Edges, TopologyEdge{
+					From:       a.ConfigID,
+					To:         b.ConfigID,
+					Kind:       TopologyEdgeABSibling,
+					Group:      group.GroupName,
+					FromWeight: a.Weight,
+					ToWeight:   b.Weight,
+				})
+			}
+		}
+	}
+
+	return topology
+}
+
+func newTopologyNode(cfg *config.LenderConfig) TopologyNode {
+	node := TopologyNode{
+		ConfigID:   cfg.ID,
+		Name:       cfg.Name,
+		UIVersion:  cfg.UIVersion,
+		FlowType:   config.GetFlowTypeFromTags(cfg.Tags),
+		Weight:     cfg.Weight,
+		SourcePath: cfg.SourcePath,
+	}
+	for _, tag := range cfg.Tags {
+		switch tag.Name {
+		case "lead_source":
+			node.LeadSources = append(node.LeadSources, tag.Value)
+		case "product_code":
+			if node.ProductCode == "" {
+				node.ProductCode = tag.Value
+			}
+		}
+	}
+	return node
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func TestBuildConfigTopology(t *testing.T) {
+	tags := func(pairs ...string) []config.Tag {
+		var result []config.Tag
+		for i := 0; i < len(pairs); i += 2 {
+			result = append(result, config.Tag{Name: pairs[i], Value: pairs[i+1]})
+		}
+		return result
+	}
+
+	configs := []*config.LenderConfig{
+		{ID: 3, Name: "evo.semi", Weight: 100, Tags: tags("product_code", "evo", "lead_source", "paid", "lead_source", "organic", "flow_type", "semi")},
+		{ID: 1, Name: "evo.auto", Weight: 70, Tags: tags("product_code", "evo", "lead_source", "organic", "flow_type", "auto")},
+		{ID: 2, Name: "evo.auto", Weight: 30, Tags: tags("product_code", "evo", "lead_source", "organic", "flow_type", "auto")},
+	}
+	groups := []ABTestingGroup{{GroupName: "evo.auto", Variants: []ABTestingVariant{{ConfigID: 1, Weight: 70}, {ConfigID: 2, Weight: 30}}}}
+
+	var calls []string
+	related := func(source *config.LenderConfig, leadSource string) []config.RelatedConfigResult {
+		calls = append(calls, source.Name+"/"+leadSource)
+		switch source.ID {
+		case 1:
+			return []config.RelatedConfigResult{{ConfigID: 2, IsABTesting: true}, {ConfigID: 3}}
+		case 3:
+			// Both lead sources find config 1; the edge is only added once
+			return []config.RelatedConfigResult{{ConfigID: 1}, {ConfigID: 99}}
+		}
+		return nil
+	}
+
+	topology := BuildConfigTopology("evo", configs, groups, related)
+
+	if len(topology.Nodes) != 3 || topology.Nodes[0].ConfigID != 1 || topology.Nodes[2].ConfigID != 3 {
+		t.Fatalf("expected nodes sorted by ID, got %+v", topology.Nodes)
+	}
+	if node := topology.Nodes[2]; node.FlowType != "semi" || node.ProductCode != "evo" || node.ClusterValue(ClusterByLeadSource) != "paid" {
+		t.Errorf("unexpected node %+v", node)
+	}
+	if len(calls) != 4 || calls[3] != "evo.semi/organic" {
+		t.Errorf("expected one lookup per lead source, got %v", calls)
+	}
+
+	want := []TopologyEdge{
+		{From: 1, To: 3, Kind: TopologyEdgeJourney, FlowType: "auto_to_semi", FromWeight: 70, ToWeight: 100},
+		{From: 3, To: 1, Kind: TopologyEdgeJourney, FlowType: "semi_to_auto", FromWeight: 100, ToWeight: 70},
+		{From: 1, To: 2, Kind: TopologyEdgeABSibling, Group: "evo.auto", FromWeight: 70, ToWeight: 30},
+	}
+	if len(topology.Edges) != len(want) {
+		t.Fatalf("expected %d edges, got %+v", len(want), topology.Edges)
+	}
+	for i := range want {
+		if topology.Edges[i] != want[i] {
+			t.Errorf("edge %d: got %+v, want %+v", i, topology.Edges[i], want[i])
+		}
+	}
+}
+
+func TestParseClusterBy(t *testing.T) {
+	for _, key := range []string{"", ClusterByLeadSource, ClusterByProductCode} {
+		if _, err := ParseClusterBy(key); err != nil {
+			t.Errorf("%q: unexpected error %v", key, err)
+		}
+	}
+	if _, err := ParseClusterBy("telco_code"); err == nil {
+		t.Error("expected an error for telco_code")
+	}
+}
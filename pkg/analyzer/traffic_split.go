@@ -0,0 +1,259 @@
+package analyzer
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultSimulatedUsers is the number of synthetic users of a traffic split simulation
+const DefaultSimulatedUsers = 10000
+
+// TrafficBuckets is the fixed number of buckets users are hashed into. It does not depend
+// on the weights, so a weight change only moves the users of the buckets changing hands.
+const TrafficBuckets = 10000
+
+// TrafficSplitOptions configures a traffic split simulation
+type TrafficSplitOptions struct {
+	// Users is the number of synthetic users, DefaultSimulatedUsers when 0
+	Users int
+	// Seed salts the user hash so different experiments bucket users independently
+	Seed string
+	// Weights overrides variant weights by config ID to preview a weight change
+	Weights map[int]int
+}
+
+// VariantShare is the traffic a variant received in a simulation
+type VariantShare struct {
+	ConfigID      int     `json:"config_id"`
+	Weight        int     `json:"weight"`
+	Users         int     `json:"users"`
+	ExpectedShare float64 `json:"expected_share"`
+	ObservedShare float64 `json:"observed_share"`
+	ExpectedUsers float64 `json:"expected_users"`
+}
+
+// TrafficSplitResult reports observed vs expected traffic of an A/B group
+type TrafficSplitResult struct {
+	Group            string         `json:"group"`
+	Users            int            `json:"users"`
+	Seed             string         `json:"seed"`
+	Variants         []VariantShare `json:"variants"`
+	ChiSquare        float64        `json:"chi_square"`
+	DegreesOfFreedom int            `json:"degrees_of_freedom"`
+	PValue           float64        `json:"p_value"`
+	// Reassigned is the number of users whose variant changes compared with the current
+	// weights; only set when weight overrides are simulated
+	Reassigned int `json:"reassigned,omitempty"`
+}
+
+// AssignVariant buckets a user into a variant of the group the way a weighted router does:
+// the user ID is hashed with the seed into one of TrafficBuckets buckets, and each variant
+// owns the range of buckets matching its slice of the cumulative weights. weights maps config
+// IDs to their weights. It returns -1 when no variant has a positive weight.
+func AssignVariant(variants []ABTestingVariant, weights map[int]int, seed, userID string) int {
+	total := 0
+	for _, variant := range variants {
+		if w := weights[variant.ConfigID]; w > 0 {
+			total += w
+		}
+	}
+	if total == 0 {
+		return -1
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	h.Write([]byte{':'})
+	h.Write([]byte(userID))
+	bucket := h.Sum64() % TrafficBuckets
+
+	cumulative := 0
+	for i, variant := range variants {
+		w := weights[variant.ConfigID]
+		if w <= 0 {
+			continue
+		}
+		cumulative += w
+		// Integer arithmetic keeps the range ends exact; the last range ends at TrafficBuckets
+		if bucket < uint64(cumulative)*TrafficBuckets/uint64(total) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SimulateTrafficSplit assigns synthetic users "user-0".."user-N-1" to the variants of a group
+// and compares the observed share of each variant with its weight. The deviation is measured
+// with Pearson's chi-square statistic over the variants with a positive weight; a small
+// PValue means the split is unlikely to come from the weights alone.
+func SimulateTrafficSplit(group ABTestingGroup, opts TrafficSplitOptions) (*TrafficSplitResult, error) {
+	users := opts.Users
+	if users <= 0 {
+		users = DefaultSimulatedUsers
+	}
+
+	current := make(map[int]int)
+	for _, variant := range group.Variants {
+		current[variant.ConfigID] = variant.Weight
+	}
+
+	weights := current
+	if len(opts.Weights) > 0 {
+		weights = make(map[int]int)
+		for id, w := range current {
+			weights[id] = w
+		}
+		for id, w := range opts.Weights {
+			if _, ok := current[id]; !ok {
+				return nil, fmt.Errorf("config %d is not a variant of group %s", id, group.GroupName)
+			}
+			weights[id] = w
+		}
+	}
+
+	total := 0
+	for _, variant := range group.Variants {
+		if w := weights[variant.ConfigID]; w > 0 {
+			total += w
+		}
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("group %s has no variant with a positive weight", group.GroupName)
+	}
+
+	result := &TrafficSplitResult{
+		Group: group.GroupName,
+		Users: users,
+		Seed:  opts.Seed,
+	}
+
+	counts := make([]int, len(group.Variants))
+	for u := 0; u < users; u++ {
+		userID := fmt.Sprintf("user-%d", u)
+		assigned := AssignVariant(group.Variants, weights, opts.Seed, userID)
+		counts[assigned]++
+
+		if len(opts.Weights) > 0 && AssignVariant(group.Variants, current, opts.Seed, userID) != assigned {
+			result.Reassigned++
+		}
+	}
+
+	categories := 0
+	for i, variant := range group.Variants {
+		w := weights[variant.ConfigID]
+		share := VariantShare{
+			ConfigID:      variant.ConfigID,
+			Weight:        w,
+			Users:         counts[i],
+			ObservedShare: float64(counts[i]) / float64(users),
+		}
+		if w > 0 {
+			share.ExpectedShare = float64(w) / float64(total)
+			share.ExpectedUsers = share.ExpectedShare * float64(users)
+
+			deviation := float64(counts[i]) - share.ExpectedUsers
+			result.ChiSquare += deviation * deviation / share.ExpectedUsers
+			categories++
+		}
+		result.Variants = append(result.Variants, share)
+	}
+
+	result.DegreesOfFreedom = categories - 1
+	result.PValue = chiSquarePValue(result.ChiSquare, result.DegreesOfFreedom)
+
+	return result, nil
+}
+
+// ParseWeightOverrides parses weight overrides such as "9012=60,9013=40"
+func ParseWeightOverrides(s string) (map[int]int, error) {
+	weights := make(map[int]int)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		idText, weightText, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid weight %q, expected config_id=weight", pair)
+		}
+		id, err := strconv.Atoi(strings.TrimSpace(idText))
+		if err != nil {
+			return nil, fmt.Errorf("invalid config ID in %q: %w", pair, err)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(weightText))
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight in %q: %w", pair, err)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("invalid weight in %q: must not be negative", pair)
+		}
+		weights[id] = weight
+	}
+	return weights, nil
+}
+
+// chiSquarePValue returns P(X >= x) for a chi-square distribution with df degrees of freedom
+func chiSquarePValue(x float64, df int) float64 {
+	if df <= 0 {
+		return 1
+	}
+	if x <= 0 {
+		return 1
+	}
+	return upperIncompleteGamma(float64(df)/2, x/2)
+}
+
+// upperIncompleteGamma returns the regularized upper incomplete gamma function Q(a, x),
+// using the series expansion for x < a+1 and a continued fraction otherwise
+func upperIncompleteGamma(a, x float64) float64 {
+	const (
+		maxIterations = 500
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(a*math.Log(x) - x - lgamma)
+
+	if x < a+1 {
+		sum := 1 / a
+		term := sum
+		for n := 1; n < maxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		return 1 - sum*prefix
+	}
+
+	// Lentz's method for the continued fraction
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < maxIterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return prefix * h
+}
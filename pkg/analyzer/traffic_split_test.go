@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSimulateTrafficSplit(t *testing.T) {
+	group := ABTestingGroup{
+		GroupName: "auto.organic",
+		Variants: []ABTestingVariant{
+			{ConfigID: 1, Weight: 50},
+			{ConfigID: 2, Weight: 50},
+		},
+		TotalWeight: 100,
+	}
+
+	result, err := SimulateTrafficSplit(group, TrafficSplitOptions{Users: 20000, Seed: "auto"})
+	if err != nil {
+		t.Fatalf("SimulateTrafficSplit failed: %v", err)
+	}
+
+	for _, v := range result.Variants {
+		if math.Abs(v.ObservedShare-0.5) > 0.02 {
+			t.Errorf("config %d: observed share %.3f too far from 0.5", v.ConfigID, v.ObservedShare)
+		}
+	}
+	if result.DegreesOfFreedom != 1 || result.PValue < 0.001 {
+		t.Errorf("unexpected deviation: chi2=%.3f df=%d p=%.4f", result.ChiSquare, result.DegreesOfFreedom, result.PValue)
+	}
+
+	// Same seed, same assignment
+	again, _ := SimulateTrafficSplit(group, TrafficSplitOptions{Users: 20000, Seed: "auto"})
+	if !reflect.DeepEqual(result, again) {
+		t.Error("expected the simulation to be deterministic")
+	}
+
+	// Moving 10 points of weight moves about 10% of the users
+	preview, err := SimulateTrafficSplit(group, TrafficSplitOptions{Users: 20000, Seed: "auto", Weights: map[int]int{1: 60, 2: 40}})
+	if err != nil {
+		t.Fatalf("SimulateTrafficSplit failed: %v", err)
+	}
+	if moved := float64(preview.Reassigned) / 20000; math.Abs(moved-0.1) > 0.02 {
+		t.Errorf("expected about 10%% of users reassigned, got %.3f", moved)
+	}
+
+	// Changing the total weight only moves the users of the buckets changing hands:
+	// 1:1 to 1:2 moves config 1 from 1/2 to 1/3 of the buckets, i.e. 1/6 of the users
+	relative := ABTestingGroup{GroupName: "collect.organic", Variants: []ABTestingVariant{{ConfigID: 1, Weight: 1}, {ConfigID: 2, Weight: 1}}}
+	preview, err = SimulateTrafficSplit(relative, TrafficSplitOptions{Users: 20000, Seed: "auto", Weights: map[int]int{2: 2}})
+	if err != nil {
+		t.Fatalf("SimulateTrafficSplit failed: %v", err)
+	}
+	if moved := float64(preview.Reassigned) / 20000; math.Abs(moved-1.0/6) > 0.02 {
+		t.Errorf("expected about 1/6 of users reassigned, got %.3f", moved)
+	}
+
+	if _, err := SimulateTrafficSplit(group, TrafficSplitOptions{Weights: map[int]int{3: 10}}); err == nil {
+		t.Error("expected an error for a weight override of an unknown config")
+	}
+}
+
+func TestChiSquarePValue(t *testing.T) {
+	tests := []struct {
+		x    float64
+		df   int
+		want float64
+	}{
+		{3.841, 1, 0.05},
+		{5.991, 2, 0.05},
+		{6.635, 1, 0.01},
+		{30, 20, 0.0699},
+	}
+
+	for _, tt := range tests {
+		if got := chiSquarePValue(tt.x, tt.df); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("chiSquarePValue(%v, %d) = %.4f, want %.4f", tt.x, tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestParseWeightOverrides(t *testing.T) {
+	weights, err := ParseWeightOverrides("9012=60, 9013=40")
+	if err != nil || !reflect.DeepEqual(weights, map[int]int{9012: 60, 9013: 40}) {
+		t.Errorf("unexpected weights %v (%v)", weights, err)
+	}
+	if _, err := ParseWeightOverrides("9012=-1"); err == nil || err.Error() != `invalid weight in "9012=-1": must not be negative` {
+		t.Errorf("expected a negative weight error, got %v", err)
+	}
+}
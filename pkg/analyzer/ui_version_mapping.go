@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"strconv"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+)
+
+// SearchTypeUIVersionMapping is the search type of a UI version mapping
+const SearchTypeUIVersionMapping = "ui_version_mapping"
+
+// Sub UI version states of a mapping row; rows of conditional sub UI versions use
+// "if <condition>" instead
+const (
+	// SubUIVersionStateNone marks a step shown with its main UI version only
+	SubUIVersionStateNone = "none"
+	// SubUIVersionStateDefault marks the sub UI version used when no condition matches
+	SubUIVersionStateDefault = "default"
+)
+
+// Active statuses of a mapping row
+const (
+	ActiveStatusActive   = "active"
+	ActiveStatusInactive = "inactive"
+)
+
+// MappingRow is one UI version a journey step can be shown with. Its columns are those of
+// the CSV QA tracks UI version coverage in.
+type MappingRow struct {
+	STT               int    `json:"stt"`
+	LenderConfigID    string `json:"lender_config_id"`
+	FlowType          string `json:"flow_type"`
+	UIVersion         string `json:"ui_version"`
+	UIFlow            string `json:"ui_flow"`
+	SubUIVersion      string `json:"sub_ui_version"`
+	SubUIVersionState string `json:"sub_ui_version_state"`
+	ActiveStatus      string `json:"active_status"`
+	TreeUUID          string `json:"tree_uuid,omitempty"`
+	Weight            string `json:"weight"`
+	Path              string `json:"path"`
+}
+
+// MappingColumns are the column names of mapping rows, in field order
+var MappingColumns = []string{
+	"STT", "LenderConfigID", "FlowType", "UIVersion", "UIFlow", "SubUIVersion",
+	"SubUIVersionState", "ActiveStatus", "TreeUUID", "Weight", "Path",
+}
+
+// Values returns the cells of the row in MappingColumns order
+func (r MappingRow) Values() []string {
+	return []string{
+		strconv.Itoa(r.STT), r.LenderConfigID, r.FlowType, r.UIVersion, r.UIFlow, r.SubUIVersion,
+		r.SubUIVersionState, r.ActiveStatus, r.TreeUUID, r.Weight, r.Path,
+	}
+}
+
+// UIVersionMapping maps every step of the journeys of a config to the UI versions it can be
+// shown with, next to the A/B testing groups of the folder
+type UIVersionMapping struct {
+	SearchID        int              `json:"search_id"`
+	SearchType      string           `json:"search_type"`
+	LeadSource      string           `json:"lead_source"`
+	Rows            []MappingRow     `json:"rows"`
+	ABTestingGroups []ABTestingGroup `json:"ab_testing_groups"`
+}
+
+// BuildUIVersionMapping flattens the journeys of a template into mapping rows: one row per
+// step with its main and default sub UI version, then one row per conditional sub UI
+// version. Weight, path and decision tree come from the target config of the journey when
+// it is in configs.
+func BuildUIVersionMapping(configID int, leadSource string, template *journey.JourneyTemplate, configs map[int]*config.LenderConfig, groups []ABTestingGroup) *UIVersionMapping {
+	mapping := &UIVersionMapping{
+		SearchID:        configID,
+		SearchType:      SearchTypeUIVersionMapping,
+		LeadSource:      leadSource,
+		Rows:            []MappingRow{},
+		ABTestingGroups: groups,
+	}
+	if template == nil {
+		return mapping
+	}
+
+	for _, j := range template.Journeys {
+		base := MappingRow{
+			LenderConfigID: strconv.Itoa(j.ToLenderConfigID),
+			FlowType:       j.FlowType,
+			ActiveStatus:   ActiveStatusInactive,
+		}
+		if j.Active {
+			base.ActiveStatus = ActiveStatusActive
+		}
+		target := configs[j.ToLenderConfigID]
+		if target != nil {
+			base.Weight = strconv.Itoa(target.Weight)
+			base.Path = target.SourcePath
+		}
+
+		for _, step := range j.Steps {
+			row := base
+			row.UIVersion = step.MainUIVersion
+			row.UIFlow = step.Name
+			if target != nil {
+				// Decision engines are keyed by the namespace of their steps, e.g. appraising
+				row.TreeUUID = target.DecisionEngines[journey.StepNamespace(step.Name)].TreeUUID
+			}
+
+			row.SubUIVersion = step.SubUIVersion
+			row.SubUIVersionState = SubUIVersionStateNone
+			if step.SubUIVersion != "" || len(step.SubUIVersionByConditions) > 0 {
+				row.SubUIVersionState = SubUIVersionStateDefault
+			}
+			mapping.addRow(row)
+
+			for _, conditional := range step.SubUIVersionByConditions {
+				row.SubUIVersion = conditional.SubUIVersion
+				row.SubUIVersionState = "if " + conditional.Condition
+				mapping.addRow(row)
+			}
+		}
+	}
+
+	return mapping
+}
+
+// addRow appends a row numbered after the previous ones
+func (m *UIVersionMapping) addRow(row MappingRow) {
+	row.STT = len(m.Rows) + 1
+	m.Rows = append(m.Rows, row)
+}
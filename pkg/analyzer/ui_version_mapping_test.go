@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+)
+
+func TestBuildUIVersionMapping(t *testing.T) {
+	template := &journey.JourneyTemplate{
+		Journeys: []journey.Journey{
+			{
+				ID: "from_1_to_2", FlowType: "cif", FromLenderConfigID: 1, ToLenderConfigID: 2, Active: true,
+				Steps: []journey.Step{
+					{ID: 1, Name: "cif.confirm", MainUIVersion: "v9.1.4.0"},
+					{ID: 2, Name: "appraising.cif", MainUIVersion: "v9.1.4.0", SubUIVersion: "v1",
+						SubUIVersionByConditions: []journey.SubUIVersionByCondition{{Condition: "telco_code=viettel", SubUIVersion: "v2"}}},
+				},
+			},
+			{
+				ID: "from_1_to_3", FlowType: "semi", FromLenderConfigID: 1, ToLenderConfigID: 3,
+				Steps: []journey.Step{{ID: 1, Name: "otp", MainUIVersion: "v9.0.0.0"}},
+			},
+		},
+	}
+	configs := map[int]*config.LenderConfig{
+		2: {
+			ID: 2, Weight: 40, SourcePath: "evo/2_organic.json",
+			DecisionEngines: map[string]config.DecisionEngine{"appraising": {TreeUUID: "tree-1"}},
+		},
+	}
+	groups := []ABTestingGroup{{GroupName: "evo.cif"}}
+
+	mapping := BuildUIVersionMapping(1, "organic", template, configs, groups)
+
+	want := []MappingRow{
+		{STT: 1, LenderConfigID: "2", FlowType: "cif", UIVersion: "v9.1.4.0", UIFlow: "cif.confirm", SubUIVersionState: "none", ActiveStatus: "active", Weight: "40", Path: "evo/2_organic.json"},
+		{STT: 2, LenderConfigID: "2", FlowType: "cif", UIVersion: "v9.1.4.0", UIFlow: "appraising.cif", SubUIVersion: "v1", SubUIVersionState: "default", ActiveStatus: "active", TreeUUID: "tree-1", Weight: "40", Path: "evo/2_organic.json"},
+		{STT: 3, LenderConfigID: "2", FlowType: "cif", UIVersion: "v9.1.4.0", UIFlow: "appraising.cif", SubUIVersion: "v2", SubUIVersionState: "if telco_code=viettel", ActiveStatus: "active", TreeUUID: "tree-1", Weight: "40", Path: "evo/2_organic.json"},
+		// Config 3 was not loaded: weight, path and tree stay empty
+		{STT: 4, LenderConfigID: "3", FlowType: "semi", UIVersion: "v9.0.0.0", UIFlow: "otp", SubUIVersionState: "none", ActiveStatus: "inactive"},
+	}
+	if !reflect.DeepEqual(mapping.Rows, want) {
+		t.Errorf("unexpected rows:\n got %+v\nwant %+v", mapping.Rows, want)
+	}
+	if mapping.SearchID != 1 || mapping.SearchType != SearchTypeUIVersionMapping || len(mapping.ABTestingGroups) != 1 {
+		t.Errorf("unexpected mapping %+v", mapping)
+	}
+
+	values := mapping.Rows[2].Values()
+	if len(values) != len(MappingColumns) || values[0] != "3" || values[6] != "if telco_code=viettel" {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	if empty := BuildUIVersionMapping(1, "organic", nil, nil, nil); empty.Rows == nil || len(empty.Rows) != 0 {
+		t.Errorf("expected no rows without a template, got %+v", empty.Rows)
+	}
+}
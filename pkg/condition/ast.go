@@ -0,0 +1,175 @@
+package condition
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is a node of a parsed condition expression
+type Node interface {
+	// String renders the node in canonical expression syntax
+	String() string
+	node()
+}
+
+// LogicalOp is a boolean connective
+type LogicalOp string
+
+// Logical operators
+const (
+	OpAnd LogicalOp = "&&"
+	OpOr  LogicalOp = "||"
+)
+
+// CompareOp is a comparison operator
+type CompareOp string
+
+// Comparison operators; "=" is accepted as an alias of "=="
+const (
+	OpEq CompareOp = "=="
+	OpNe CompareOp = "!="
+	OpLt CompareOp = "<"
+	OpLe CompareOp = "<="
+	OpGt CompareOp = ">"
+	OpGe CompareOp = ">="
+)
+
+// LiteralKind is the type of a literal value
+type LiteralKind int
+
+// Literal kinds
+const (
+	LiteralString LiteralKind = iota
+	LiteralNumber
+	LiteralBool
+)
+
+// Literal is a constant value in an expression
+type Literal struct {
+	Kind LiteralKind
+	Text string
+}
+
+// Logical combines two expressions with && or ||
+type Logical struct {
+	Op    LogicalOp
+	Left  Node
+	Right Node
+}
+
+// Not negates an expression
+type Not struct {
+	X Node
+}
+
+// Comparison compares an attribute with a literal
+type Comparison struct {
+	Attr  string
+	Op    CompareOp
+	Value Literal
+}
+
+// In tests whether an attribute is one of a list of literals
+type In struct {
+	Attr    string
+	Values  []Literal
+	Negated bool
+}
+
+// Truthy tests that a bare attribute is set to a true value
+type Truthy struct {
+	Attr string
+}
+
+func (*Logical) node()    {}
+func (*Not) node()        {}
+func (*Comparison) node() {}
+func (*In) node()         {}
+func (*Truthy) node()     {}
+
+func (n *Logical) String() string {
+	return fmt.Sprintf("%s %s %s", wrap(n.Left, n.Op), n.Op, wrap(n.Right, n.Op))
+}
+
+func (n *Not) String() string {
+	switch n.X.(type) {
+	case *Logical:
+		return "!(" + n.X.String() + ")"
+	default:
+		return "!" + n.X.String()
+	}
+}
+
+func (n *Comparison) String() string {
+	return fmt.Sprintf("%s %s %s", n.Attr, n.Op, n.Value)
+}
+
+func (n *In) String() string {
+	values := make([]string, len(n.Values))
+	for i, v := range n.Values {
+		values[i] = v.String()
+	}
+	op := "in"
+	if n.Negated {
+		op = "not in"
+	}
+	return fmt.Sprintf("%s %s [%s]", n.Attr, op, strings.Join(values, ", "))
+}
+
+func (n *Truthy) String() string {
+	return n.Attr
+}
+
+// String renders the literal, quoting strings
+func (l Literal) String() string {
+	if l.Kind == LiteralString {
+		return strconv.Quote(l.Text)
+	}
+	return l.Text
+}
+
+// wrap parenthesizes an || operand nested under &&
+func wrap(n Node, parent LogicalOp) string {
+	if child, ok := n.(*Logical); ok && parent == OpAnd && child.Op == OpOr {
+		return "(" + n.String() + ")"
+	}
+	return n.String()
+}
+
+// ReferencedAttributes returns the attribute names referenced by an expression, in order of appearance
+func ReferencedAttributes(n Node) []string {
+	var names []string
+	seen := make(map[string]bool)
+	Walk(n, func(n Node) {
+		var attr string
+		switch x := n.(type) {
+		case *Comparison:
+			attr = x.Attr
+		case *In:
+			attr = x.Attr
+		case *Truthy:
+			attr = x.Attr
+		}
+		if attr != "" && !seen[attr] {
+			seen[attr] = true
+			names = append(names, attr)
+		}
+	})
+	return names
+}
+
+// Walk calls fn for every node of the expression in depth-first order
+func Walk(n Node, fn func(Node)) {
+	if n == nil {
+		return
+	}
+	fn(n)
+	switch x := n.(type) {
+	case *Logical:
+		Walk(x.Left, fn)
+		Walk(x.Right, fn)
+	case *Not:
+		Walk(x.X, fn)
+	}
+}
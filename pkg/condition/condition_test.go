@@ -0,0 +1,97 @@
+package condition
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAndEval(t *testing.T) {
+	attrs := Attributes{
+		"communication_call":     "success",
+		"lead_source":            "organic",
+		"telco_code":             "viettel",
+		"score":                  "640",
+		"flow_routing_condition": "true",
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"flow_routing_condition == true", true},
+		{"communication_call=success, lead_source=organic", true},
+		{"communication_call=success, lead_source=paid", false},
+		{"lead_source in [organic, paid] && !(telco_code == viettel)", false},
+		{"telco_code not in ('mobifone', \"vinaphone\") or score < 600", true},
+		{"score >= 600 and not lead_source = paid", true},
+		{"lead_source == paid || flow_routing_condition", true},
+		// The missing attribute does not matter once && is decided
+		{"lead_source == paid && unknown_attr == x", false},
+	}
+
+	for _, tt := range tests {
+		got, err := EvalString(tt.expr, attrs)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestEvalMissingAttribute(t *testing.T) {
+	_, err := EvalString("communication_call=success", Attributes{})
+	var missing *MissingAttributeError
+	if !errors.As(err, &missing) || missing.Attr != "communication_call" {
+		t.Errorf("expected MissingAttributeError for communication_call, got %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "a ==", "a == b &&", "(a == b", "a in [x", "a & b", "== b"} {
+		_, err := Parse(expr)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%q: expected SyntaxError, got %v", expr, err)
+		}
+	}
+}
+
+func TestSyntaxErrorPosCountsRunes(t *testing.T) {
+	// "é" is two bytes but one rune: the lone & is at rune 9, byte 10
+	_, err := Parse(`name="é" & x`)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Pos != 9 {
+		t.Errorf("expected SyntaxError at rune 9, got %v", err)
+	}
+}
+
+func TestStringAndDescribe(t *testing.T) {
+	node := MustParse("communication_call=success, (lead_source=organic or lead_source=paid)")
+
+	if got, want := node.String(), `communication_call == "success" && (lead_source == "organic" || lead_source == "paid")`; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	if got, want := Describe(node), "communication_call equals success and (lead_source equals organic or lead_source equals paid)"; got != want {
+		t.Errorf("Describe() = %s, want %s", got, want)
+	}
+
+	// Canonical form parses back to the same expression
+	if again := MustParse(node.String()).String(); again != node.String() {
+		t.Errorf("round trip changed expression: %s", again)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema := Schema{
+		"lead_source": {"organic", "paid"},
+		"score":       nil,
+	}
+
+	errs := Validate(MustParse("lead_source in [organic, referral] && score > high && telco == x"), schema)
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 validation errors, got %v", errs)
+	}
+}
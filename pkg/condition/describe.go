@@ -0,0 +1,71 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Describe renders an expression as plain words, suitable for diagram labels where
+// operators such as "==" or "&&" are awkward
+func Describe(n Node) string {
+	switch x := n.(type) {
+	case *Logical:
+		word := "and"
+		if x.Op == OpOr {
+			word = "or"
+		}
+		return fmt.Sprintf("%s %s %s", describeOperand(x.Left, x.Op), word, describeOperand(x.Right, x.Op))
+	case *Not:
+		return "not (" + Describe(x.X) + ")"
+	case *Comparison:
+		return fmt.Sprintf("%s %s %s", x.Attr, describeOp(x.Op), x.Value.Text)
+	case *In:
+		values := make([]string, len(x.Values))
+		for i, v := range x.Values {
+			values[i] = v.Text
+		}
+		if x.Negated {
+			return fmt.Sprintf("%s not in %s", x.Attr, strings.Join(values, "/"))
+		}
+		return fmt.Sprintf("%s in %s", x.Attr, strings.Join(values, "/"))
+	case *Truthy:
+		return x.Attr
+	default:
+		return ""
+	}
+}
+
+// DescribeString describes an expression, falling back to the raw text when it does not parse
+func DescribeString(expr string) string {
+	node, err := Parse(expr)
+	if err != nil {
+		return expr
+	}
+	return Describe(node)
+}
+
+func describeOperand(n Node, parent LogicalOp) string {
+	if child, ok := n.(*Logical); ok && parent == OpAnd && child.Op == OpOr {
+		return "(" + Describe(n) + ")"
+	}
+	return Describe(n)
+}
+
+func describeOp(op CompareOp) string {
+	switch op {
+	case OpEq:
+		return "equals"
+	case OpNe:
+		return "not equals"
+	case OpLt:
+		return "less than"
+	case OpLe:
+		return "at most"
+	case OpGt:
+		return "greater than"
+	case OpGe:
+		return "at least"
+	default:
+		return string(op)
+	}
+}
@@ -0,0 +1,170 @@
+package condition
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Attributes of a user, e.g. lead_source=organic, telco_code=viettel
+type Attributes map[string]string
+
+// MissingAttributeError reports an attribute referenced by a condition but absent from the input
+type MissingAttributeError struct {
+	Attr string
+}
+
+func (e *MissingAttributeError) Error() string {
+	return fmt.Sprintf("attribute %q is not set", e.Attr)
+}
+
+// Eval evaluates an expression against user attributes. A comparison on an attribute
+// that is not set yields a *MissingAttributeError unless the result is already decided
+// by the other operand of && or ||.
+func Eval(n Node, attrs Attributes) (bool, error) {
+	switch x := n.(type) {
+	case *Logical:
+		left, leftErr := Eval(x.Left, attrs)
+		if leftErr == nil {
+			if x.Op == OpAnd && !left {
+				return false, nil
+			}
+			if x.Op == OpOr && left {
+				return true, nil
+			}
+		}
+
+		right, rightErr := Eval(x.Right, attrs)
+		if rightErr == nil {
+			if x.Op == OpAnd && !right {
+				return false, nil
+			}
+			if x.Op == OpOr && right {
+				return true, nil
+			}
+		}
+
+		if leftErr != nil {
+			return false, leftErr
+		}
+		if rightErr != nil {
+			return false, rightErr
+		}
+		return right, nil
+	case *Not:
+		value, err := Eval(x.X, attrs)
+		if err != nil {
+			return false, err
+		}
+		return !value, nil
+	case *Comparison:
+		actual, ok := attrs[x.Attr]
+		if !ok {
+			return false, &MissingAttributeError{Attr: x.Attr}
+		}
+		return compare(actual, x.Op, x.Value)
+	case *In:
+		actual, ok := attrs[x.Attr]
+		if !ok {
+			return false, &MissingAttributeError{Attr: x.Attr}
+		}
+		for _, value := range x.Values {
+			equal, err := compare(actual, OpEq, value)
+			if err != nil {
+				return false, err
+			}
+			if equal {
+				return !x.Negated, nil
+			}
+		}
+		return x.Negated, nil
+	case *Truthy:
+		actual, ok := attrs[x.Attr]
+		if !ok {
+			return false, &MissingAttributeError{Attr: x.Attr}
+		}
+		value, err := strconv.ParseBool(actual)
+		if err != nil {
+			return actual != "", nil
+		}
+		return value, nil
+	default:
+		return false, fmt.Errorf("unsupported condition node %T", n)
+	}
+}
+
+// EvalString parses and evaluates an expression
+func EvalString(expr string, attrs Attributes) (bool, error) {
+	node, err := Parse(expr)
+	if err != nil {
+		return false, err
+	}
+	return Eval(node, attrs)
+}
+
+// ParseAttributes parses a comma separated list of key=value pairs, e.g.
+// "lead_source=organic,telco_code=viettel"
+func ParseAttributes(s string) (Attributes, error) {
+	attrs := make(Attributes)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid attribute %q, expected key=value", pair)
+		}
+		attrs[key] = strings.TrimSpace(value)
+	}
+	return attrs, nil
+}
+
+// compare compares an attribute value with a literal according to the literal's kind
+func compare(actual string, op CompareOp, value Literal) (bool, error) {
+	switch value.Kind {
+	case LiteralNumber:
+		a, err := strconv.ParseFloat(strings.TrimSpace(actual), 64)
+		if err != nil {
+			return false, fmt.Errorf("attribute value %q is not a number", actual)
+		}
+		b, _ := strconv.ParseFloat(value.Text, 64)
+		return compareOrdered(a, b, op), nil
+	case LiteralBool:
+		a, err := strconv.ParseBool(strings.TrimSpace(actual))
+		if err != nil {
+			return false, fmt.Errorf("attribute value %q is not a boolean", actual)
+		}
+		b := value.Text == "true"
+		switch op {
+		case OpEq:
+			return a == b, nil
+		case OpNe:
+			return a != b, nil
+		default:
+			return false, fmt.Errorf("operator %s is not defined for booleans", op)
+		}
+	default:
+		return compareOrdered(actual, value.Text, op), nil
+	}
+}
+
+func compareOrdered[T float64 | string](a, b T, op CompareOp) bool {
+	switch op {
+	case OpEq:
+		return a == b
+	case OpNe:
+		return a != b
+	case OpLt:
+		return a < b
+	case OpLe:
+		return a <= b
+	case OpGt:
+		return a > b
+	case OpGe:
+		return a >= b
+	default:
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind identifies the lexical class of a token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOp
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenIn
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+)
+
+// token is a lexical token with its rune index in the source expression
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// SyntaxError reports an invalid expression with the offset of the offending token
+type SyntaxError struct {
+	Expr string
+	// Pos is the index of the offending token in the runes (not bytes) of Expr
+	Pos     int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid condition %q at offset %d: %s", e.Expr, e.Pos, e.Message)
+}
+
+// lex splits an expression into tokens. Identifiers may contain letters, digits,
+// '_', '.', '-' and '/' so step names and UI versions can be written unquoted.
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{tokenLBracket, "[", i})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{tokenRBracket, "]", i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ",", i})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, &SyntaxError{Expr: expr, Pos: i, Message: fmt.Sprintf("expected %c%c", r, r)}
+			}
+			kind := tokenAnd
+			if r == '|' {
+				kind = tokenOr
+			}
+			tokens = append(tokens, token{kind, string([]rune{r, r}), i})
+			i += 2
+		case r == '=' || r == '!' || r == '<' || r == '>':
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				tokens = append(tokens, token{tokenNot, op, i})
+			} else {
+				tokens = append(tokens, token{tokenOp, op, i})
+			}
+			i += len(op)
+		case r == '"' || r == '\'':
+			start := i
+			var text strings.Builder
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				text.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, &SyntaxError{Expr: expr, Pos: start, Message: "unterminated string"}
+			}
+			tokens = append(tokens, token{tokenString, text.String(), start})
+			i++
+		case isWordRune(r):
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			tokens = append(tokens, wordToken(word, start))
+		default:
+			return nil, &SyntaxError{Expr: expr, Pos: i, Message: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	return append(tokens, token{tokenEOF, "", len(runes)}), nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-' || r == '/'
+}
+
+// wordToken classifies a bare word as keyword, number or identifier
+func wordToken(word string, pos int) token {
+	switch strings.ToLower(word) {
+	case "and":
+		return token{tokenAnd, word, pos}
+	case "or":
+		return token{tokenOr, word, pos}
+	case "not":
+		return token{tokenNot, word, pos}
+	case "in":
+		return token{tokenIn, word, pos}
+	}
+	if isNumber(word) {
+		return token{tokenNumber, word, pos}
+	}
+	return token{tokenIdent, word, pos}
+}
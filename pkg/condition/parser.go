@@ -0,0 +1,253 @@
+package condition
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse parses a condition expression.
+//
+// The grammar accepts the forms used in journey templates and configs:
+//
+//	flow_routing_condition == true
+//	communication_call=success, lead_source=organic
+//	lead_source in [organic, paid] && !(telco_code == viettel)
+//	telco_code not in ("viettel", "mobifone") or score >= 600
+//
+// "," and "and" are aliases of "&&", "or" of "||", "not" of "!" and "=" of "==".
+// Unquoted words are string literals, except numbers and true/false.
+func Parse(expr string) (Node, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{expr: expr, tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorf(p.peek(), "empty condition")
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+
+	return node, nil
+}
+
+// MustParse parses an expression and panics on error; intended for constants
+func MustParse(expr string) Node {
+	node, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+type parser struct {
+	expr   string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	return &SyntaxError{Expr: p.expr, Pos: tok.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: OpOr, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd || p.peek().kind == tokenComma {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: OpAnd, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorf(closing, "expected ')'")
+		}
+		return node, nil
+	case tokenIdent:
+		return p.parseComparison(tok.text)
+	default:
+		return nil, p.errorf(tok, "expected attribute name, got %q", tok.text)
+	}
+}
+
+func (p *parser) parseComparison(attr string) (Node, error) {
+	tok := p.peek()
+
+	switch {
+	case tok.kind == tokenOp:
+		p.next()
+		op, err := p.compareOp(tok)
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return &Comparison{Attr: attr, Op: op, Value: value}, nil
+	case tok.kind == tokenIn:
+		p.next()
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &In{Attr: attr, Values: values}, nil
+	case tok.kind == tokenNot && strings.EqualFold(tok.text, "not") && p.tokens[p.pos+1].kind == tokenIn:
+		p.next()
+		p.next()
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &In{Attr: attr, Values: values, Negated: true}, nil
+	case tok.kind == tokenNot:
+		return nil, p.errorf(tok, "unexpected %q after attribute %q", tok.text, attr)
+	default:
+		return &Truthy{Attr: attr}, nil
+	}
+}
+
+func (p *parser) compareOp(tok token) (CompareOp, error) {
+	switch tok.text {
+	case "=", "==":
+		return OpEq, nil
+	case "!=":
+		return OpNe, nil
+	case "<":
+		return OpLt, nil
+	case "<=":
+		return OpLe, nil
+	case ">":
+		return OpGt, nil
+	case ">=":
+		return OpGe, nil
+	default:
+		return "", p.errorf(tok, "unknown operator %q", tok.text)
+	}
+}
+
+func (p *parser) parseLiteral() (Literal, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenString:
+		return Literal{Kind: LiteralString, Text: tok.text}, nil
+	case tokenNumber:
+		return Literal{Kind: LiteralNumber, Text: tok.text}, nil
+	case tokenIdent:
+		if lower := strings.ToLower(tok.text); lower == "true" || lower == "false" {
+			return Literal{Kind: LiteralBool, Text: lower}, nil
+		}
+		return Literal{Kind: LiteralString, Text: tok.text}, nil
+	default:
+		return Literal{}, p.errorf(tok, "expected value, got %q", tok.text)
+	}
+}
+
+func (p *parser) parseList() ([]Literal, error) {
+	open := p.next()
+	var closeKind tokenKind
+	switch open.kind {
+	case tokenLBracket:
+		closeKind = tokenRBracket
+	case tokenLParen:
+		closeKind = tokenRParen
+	default:
+		return nil, p.errorf(open, "expected '[' or '(' after in")
+	}
+
+	var values []Literal
+	for {
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		tok := p.next()
+		if tok.kind == closeKind {
+			return values, nil
+		}
+		if tok.kind != tokenComma {
+			return nil, p.errorf(tok, "expected ',' or end of list")
+		}
+	}
+}
+
+// isNumber reports whether text is a decimal number; words such as "inf" stay identifiers
+func isNumber(text string) bool {
+	digits := strings.TrimLeft(text, "+-")
+	if digits == "" || digits[0] < '0' || digits[0] > '9' {
+		return false
+	}
+	_, err := strconv.ParseFloat(text, 64)
+	return err == nil
+}
@@ -0,0 +1,72 @@
+package condition
+
+import (
+	"fmt"
+)
+
+// Schema declares the attributes a condition may reference and, optionally, their allowed
+// values. A nil value list accepts any value.
+type Schema map[string][]string
+
+// ValidationError describes a semantic problem in a parsed expression
+type ValidationError struct {
+	Attr    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Attr, e.Message)
+}
+
+// Validate checks an expression for unknown attributes, values outside the allowed set and
+// ordering comparisons on non-numeric literals. A nil schema only performs the type checks.
+func Validate(n Node, schema Schema) []error {
+	var errs []error
+
+	checkAttr := func(attr string) ([]string, bool) {
+		if schema == nil {
+			return nil, true
+		}
+		allowed, ok := schema[attr]
+		if !ok {
+			errs = append(errs, &ValidationError{Attr: attr, Message: "unknown attribute"})
+		}
+		return allowed, ok
+	}
+
+	checkValue := func(attr string, allowed []string, value Literal) {
+		if allowed == nil {
+			return
+		}
+		for _, candidate := range allowed {
+			if candidate == value.Text {
+				return
+			}
+		}
+		errs = append(errs, &ValidationError{Attr: attr, Message: fmt.Sprintf("value %s is not one of %v", value, allowed)})
+	}
+
+	Walk(n, func(n Node) {
+		switch x := n.(type) {
+		case *Comparison:
+			allowed, ok := checkAttr(x.Attr)
+			if ok {
+				checkValue(x.Attr, allowed, x.Value)
+			}
+			if x.Op != OpEq && x.Op != OpNe && x.Value.Kind != LiteralNumber {
+				errs = append(errs, &ValidationError{Attr: x.Attr, Message: fmt.Sprintf("operator %s requires a number, got %s", x.Op, x.Value)})
+			}
+		case *In:
+			allowed, ok := checkAttr(x.Attr)
+			if ok {
+				for _, value := range x.Values {
+					checkValue(x.Attr, allowed, value)
+				}
+			}
+		case *Truthy:
+			checkAttr(x.Attr)
+		}
+	})
+
+	return errs
+}
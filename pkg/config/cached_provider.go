@@ -0,0 +1,413 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedConfigProvider - load toàn bộ config tree một lần với worker pool, index theo ID,
+// name và tag, và phục vụ LoadConfig/LoadConfigs từ memory.
+//
+// The tree is walked once; later calls only re-stat the known files and directories and
+// re-parse the files whose size or modification time changed. The tree is walked again when
+// a directory changed (a file was added, removed or renamed) or on Refresh. Returned indexes
+// and configs are shared between callers and must not be modified.
+type CachedConfigProvider struct {
+	BasePath string
+	Workers  int
+
+	// mu guards index; refresh serializes the rebuilds so concurrent callers share one
+	mu      sync.Mutex
+	refresh sync.Mutex
+	index   *ConfigIndex
+}
+
+// ConfigIndex is an in-memory snapshot of a config tree
+type ConfigIndex struct {
+	// Configs in file walk order
+	Configs []*LenderConfig
+	// paths are all JSON files in walk order, including the ones that failed to load
+	paths []string
+
+	byID   map[int][]*LenderConfig
+	byName map[string][]*LenderConfig
+	byTag  map[Tag][]*LenderConfig
+	files  map[string]cachedFile
+	// dirs are the walked directories and their modification time at the walk
+	dirs map[string]time.Time
+	// scanErrors are the paths the walk could not read
+	scanErrors []LoadDiagnostic
+}
+
+// cachedFile is a parsed file and the stat it was parsed at; config is nil and diagnostic
+// is set when the file could not be loaded
+type cachedFile struct {
+	size       int64
+	modTime    time.Time
+	config     *LenderConfig
+	diagnostic *LoadDiagnostic
+}
+
+// NewCachedConfigProvider tạo cached provider; workers <= 0 dùng số CPU
+func NewCachedConfigProvider(basePath string, workers int) *CachedConfigProvider {
+	return &CachedConfigProvider{
+		BasePath: basePath,
+		Workers:  workers,
+	}
+}
+
+// LoadConfigs trả về các configs nằm dưới BasePath/path; files that cannot be loaded are skipped
+func (p *CachedConfigProvider) LoadConfigs(ctx context.Context, path string) ([]*LenderConfig, error) {
+	configs, _, err := p.LoadConfigsWithReport(ctx, path)
+	return configs, err
+}
+
+// LoadConfigsWithReport trả về các configs nằm dưới BasePath/path, kèm diagnostics của các
+// files không load được
+func (p *CachedConfigProvider) LoadConfigsWithReport(ctx context.Context, path string) ([]*LenderConfig, *LoadReport, error) {
+	index, err := p.Index(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := filepath.Clean(filepath.Join(p.BasePath, path))
+	var configs []*LenderConfig
+	report := &LoadReport{}
+	if _, err := os.Stat(root); err != nil {
+		report.add(LoadDiagnostic{Path: root, Reason: err.Error()})
+	}
+	for _, d := range index.scanErrors {
+		if isUnder(d.Path, root) || isUnder(root, d.Path) {
+			report.add(d)
+		}
+	}
+	for _, filePath := range index.paths {
+		if !isUnder(filePath, root) {
+			continue
+		}
+		report.Files++
+		file := index.files[filePath]
+		if file.diagnostic != nil {
+			report.add(*file.diagnostic)
+			continue
+		}
+		configs = append(configs, file.config)
+		report.Loaded++
+	}
+
+	report.Duplicates = FindDuplicateIDs(configs)
+	return configs, report, nil
+}
+
+// LoadConfig tìm config theo ID trong index, whatever the name of its file; several matches
+// are an *AmbiguousConfigError
+func (p *CachedConfigProvider) LoadConfig(ctx context.Context, configID int, leadSource string) (*LenderConfig, error) {
+	index, err := p.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*LenderConfig
+	for _, config := range index.byID[configID] {
+		if hasLeadSource(config, leadSource) {
+			matches = append(matches, config)
+		}
+	}
+
+	config, err := selectConfig(configID, leadSource, matches)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, fmt.Errorf("config %d not found", configID)
+	}
+	return config, nil
+}
+
+// ConfigsByName trả về các configs có name
+func (p *CachedConfigProvider) ConfigsByName(ctx context.Context, name string) ([]*LenderConfig, error) {
+	index, err := p.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return index.byName[name], nil
+}
+
+// ConfigsByTag trả về các configs có tag (name, value)
+func (p *CachedConfigProvider) ConfigsByTag(ctx context.Context, name, value string) ([]*LenderConfig, error) {
+	index, err := p.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return index.byTag[Tag{Name: name, Value: value}], nil
+}
+
+// Index returns the current snapshot of the tree, refreshing files changed on disk
+func (p *CachedConfigProvider) Index(ctx context.Context) (*ConfigIndex, error) {
+	current := p.snapshot()
+	if current == nil {
+		return p.rebuild(ctx, nil, nil)
+	}
+
+	stats, ok := current.restat()
+	if !ok {
+		// A directory changed or a file is gone: walk the tree again
+		return p.rebuild(ctx, current, nil)
+	}
+	if current.upToDate(current.paths, stats) {
+		return current, nil
+	}
+	return p.rebuild(ctx, current, stats)
+}
+
+// Refresh walks the whole tree again and returns the new snapshot
+func (p *CachedConfigProvider) Refresh(ctx context.Context) (*ConfigIndex, error) {
+	return p.rebuild(ctx, p.snapshot(), nil)
+}
+
+func (p *CachedConfigProvider) snapshot() *ConfigIndex {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.index
+}
+
+// rebuild builds a new snapshot from base, walking the tree when stats is nil, and swaps it
+// in; snapshots are never modified once returned
+func (p *CachedConfigProvider) rebuild(ctx context.Context, base *ConfigIndex, stats map[string]os.FileInfo) (*ConfigIndex, error) {
+	p.refresh.Lock()
+	defer p.refresh.Unlock()
+
+	// Another caller rebuilt the index while this one was waiting
+	if latest := p.snapshot(); latest != base {
+		return latest, nil
+	}
+
+	var previous map[string]cachedFile
+	if base != nil {
+		previous = base.files
+	}
+
+	var paths []string
+	var dirs map[string]time.Time
+	var scanErrors []LoadDiagnostic
+	if stats == nil {
+		var err error
+		paths, stats, dirs, scanErrors, err = p.scan()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		paths, dirs, scanErrors = base.paths, base.dirs, base.scanErrors
+	}
+
+	files, err := p.load(ctx, paths, stats, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	index := buildIndex(paths, files)
+	index.dirs = dirs
+	index.scanErrors = scanErrors
+
+	p.mu.Lock()
+	p.index = index
+	p.mu.Unlock()
+	return index, nil
+}
+
+// scan lists the JSON files of the tree in walk order, skipping archive directories, the
+// walked directories and the paths that could not be walked
+func (p *CachedConfigProvider) scan() ([]string, map[string]os.FileInfo, map[string]time.Time, []LoadDiagnostic, error) {
+	var paths []string
+	var scanErrors []LoadDiagnostic
+	stats := make(map[string]os.FileInfo)
+	dirs := make(map[string]time.Time)
+
+	err := filepath.Walk(p.BasePath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			scanErrors = append(scanErrors, LoadDiagnostic{Path: filePath, Reason: err.Error()})
+			return nil
+		}
+
+		// Skip archive directories
+		if info.IsDir() && strings.Contains(strings.ToLower(info.Name()), "archive") {
+			return filepath.SkipDir
+		}
+
+		if info.IsDir() {
+			dirs[filePath] = info.ModTime()
+		} else if strings.HasSuffix(info.Name(), ".json") {
+			paths = append(paths, filePath)
+			stats[filePath] = info
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to scan configs from %s: %w", p.BasePath, err)
+	}
+
+	return paths, stats, dirs, scanErrors, nil
+}
+
+// load parses new and modified files with a bounded worker pool and reuses the others
+func (p *CachedConfigProvider) load(ctx context.Context, paths []string, stats map[string]os.FileInfo, previous map[string]cachedFile) (map[string]cachedFile, error) {
+	files := make(map[string]cachedFile, len(paths))
+
+	var stale []string
+	for _, filePath := range paths {
+		info := stats[filePath]
+		if cached, ok := previous[filePath]; ok && cached.matches(info) {
+			files[filePath] = cached
+			continue
+		}
+		stale = append(stale, filePath)
+	}
+
+	workers := p.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan string)
+	results := make(chan struct {
+		path string
+		file cachedFile
+	})
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(stale)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for filePath := range jobs {
+				info := stats[filePath]
+				file := cachedFile{size: info.Size(), modTime: info.ModTime()}
+				data, err := os.ReadFile(filePath)
+				if err == nil {
+					file.config, err = parseConfig(data, filePath)
+				}
+				if err != nil {
+					d := newLoadDiagnostic(filePath, data, err)
+					file.diagnostic = &d
+				}
+				results <- struct {
+					path string
+					file cachedFile
+				}{filePath, file}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, filePath := range stale {
+			select {
+			case jobs <- filePath:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		files[result.path] = result.file
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load configs from %s: %w", p.BasePath, err)
+	}
+
+	return files, nil
+}
+
+// Duplicates returns the config IDs declared by more than one file of the tree
+func (idx *ConfigIndex) Duplicates() []DuplicateConfigID {
+	return FindDuplicateIDs(idx.Configs)
+}
+
+// restat stats the directories and files of the snapshot without walking the tree; ok is
+// false when a directory changed or a file cannot be stat'ed, which needs a new walk
+func (idx *ConfigIndex) restat() (stats map[string]os.FileInfo, ok bool) {
+	for dir, modTime := range idx.dirs {
+		info, err := os.Lstat(dir)
+		if err != nil || !info.ModTime().Equal(modTime) {
+			return nil, false
+		}
+	}
+
+	stats = make(map[string]os.FileInfo, len(idx.paths))
+	for _, filePath := range idx.paths {
+		info, err := os.Lstat(filePath)
+		if err != nil {
+			return nil, false
+		}
+		stats[filePath] = info
+	}
+	return stats, true
+}
+
+// upToDate reports whether the index was built from exactly these files at these stats
+func (idx *ConfigIndex) upToDate(paths []string, stats map[string]os.FileInfo) bool {
+	if len(paths) != len(idx.files) {
+		return false
+	}
+	for _, filePath := range paths {
+		cached, ok := idx.files[filePath]
+		if !ok || !cached.matches(stats[filePath]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f cachedFile) matches(info os.FileInfo) bool {
+	return f.size == info.Size() && f.modTime.Equal(info.ModTime())
+}
+
+// buildIndex indexes the parsed configs in walk order
+func buildIndex(paths []string, files map[string]cachedFile) *ConfigIndex {
+	index := &ConfigIndex{
+		byID:   make(map[int][]*LenderConfig),
+		byName: make(map[string][]*LenderConfig),
+		byTag:  make(map[Tag][]*LenderConfig),
+		files:  files,
+		paths:  paths,
+	}
+
+	for _, filePath := range paths {
+		config := files[filePath].config
+		if config == nil {
+			continue
+		}
+
+		index.Configs = append(index.Configs, config)
+		index.byID[config.ID] = append(index.byID[config.ID], config)
+		index.byName[config.Name] = append(index.byName[config.Name], config)
+		for _, tag := range config.Tags {
+			index.byTag[tag] = append(index.byTag[tag], config)
+		}
+	}
+
+	return index
+}
+
+// isUnder reports whether filePath is root or inside root
+func isUnder(filePath, root string) bool {
+	rel, err := filepath.Rel(root, filePath)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCachedConfigProvider(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		file := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	organic := write("evo/9054_organic.json", `{"id": 9054, "name": "collect", "ui_version": "v9.1.5.0", "tags": [{"name": "lead_source", "value": "organic"}]}`)
+	write("evo/9055_paid.json", `{"id": 9055, "name": "collect", "ui_version": "v9.1.5.0", "tags": [{"name": "lead_source", "value": "paid"}]}`)
+	write("evo/archive/9001.json", `{"id": 9001}`)
+	write("evo/broken.json", `{not json`)
+	write("other/9100.json", `{"id": 9100, "name": "other"}`)
+
+	ctx := context.Background()
+	provider := NewCachedConfigProvider(root, 2)
+
+	configs, err := provider.LoadConfigs(ctx, "evo")
+	if err != nil {
+		t.Fatalf("LoadConfigs failed: %v", err)
+	}
+	if len(configs) != 2 || configs[0].ID != 9054 || configs[1].ID != 9055 {
+		t.Fatalf("expected configs 9054 and 9055 in walk order, got %+v", configs)
+	}
+
+	all, err := provider.LoadConfigs(ctx, "")
+	if err != nil {
+		t.Fatalf("LoadConfigs failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 configs outside archives, got %d", len(all))
+	}
+
+	if _, err := provider.LoadConfig(ctx, 9054, "paid"); err == nil {
+		t.Error("expected config 9054 not to match lead source paid")
+	}
+	cfg, err := provider.LoadConfig(ctx, 9054, "organic")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.SourcePath != organic {
+		t.Errorf("expected source path %s, got %s", organic, cfg.SourcePath)
+	}
+
+	byName, err := provider.ConfigsByName(ctx, "collect")
+	if err != nil || len(byName) != 2 {
+		t.Errorf("expected 2 configs named collect, got %d (%v)", len(byName), err)
+	}
+	byTag, err := provider.ConfigsByTag(ctx, "lead_source", "paid")
+	if err != nil || len(byTag) != 1 || byTag[0].ID != 9055 {
+		t.Errorf("expected config 9055 tagged lead_source=paid, got %+v (%v)", byTag, err)
+	}
+
+	// An unchanged tree is served from the same snapshot
+	first, _ := provider.Index(ctx)
+	second, _ := provider.Index(ctx)
+	if first != second {
+		t.Error("expected the index to be reused while no file changed")
+	}
+
+	// Modified, added and removed files invalidate the snapshot
+	write("evo/9054_organic.json", `{"id": 9054, "name": "collect", "ui_version": "v9.1.6.0", "tags": [{"name": "lead_source", "value": "organic"}]}`)
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(organic, later, later); err != nil {
+		t.Fatal(err)
+	}
+	write("evo/9056.json", `{"id": 9056, "name": "collect.new"}`)
+	if err := os.Remove(filepath.Join(root, "evo/9055_paid.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = provider.LoadConfig(ctx, 9054, "")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.UIVersion != "v9.1.6.0" {
+		t.Errorf("expected the modified config to be reloaded, got ui_version %s", cfg.UIVersion)
+	}
+	if _, err := provider.LoadConfig(ctx, 9056, ""); err != nil {
+		t.Errorf("expected the added config to be found: %v", err)
+	}
+	if _, err := provider.LoadConfig(ctx, 9055, ""); err == nil {
+		t.Error("expected the removed config not to be found")
+	}
+	if byTag, _ := provider.ConfigsByTag(ctx, "lead_source", "paid"); len(byTag) != 0 {
+		t.Errorf("expected no config tagged lead_source=paid, got %+v", byTag)
+	}
+}
+
+func TestCachedConfigProviderConcurrent(t *testing.T) {
+	root := t.TempDir()
+	// Files are replaced atomically so readers never see a partial write
+	write := func(name, content string) {
+		t.Helper()
+		tmp := filepath.Join(root, name+".tmp")
+		if err := os.WriteFile(tmp, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, filepath.Join(root, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("9054_organic.json", `{"id": 9054, "ui_version": "v1"}`)
+
+	ctx := context.Background()
+	provider := NewCachedConfigProvider(root, 2)
+	first, err := provider.Index(ctx)
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, _, err := provider.LoadConfigsWithReport(ctx, ""); err != nil {
+					t.Errorf("LoadConfigsWithReport failed: %v", err)
+				}
+				if _, err := provider.LoadConfig(ctx, 9054, ""); err != nil {
+					t.Errorf("LoadConfig failed: %v", err)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		write("9054_organic.json", fmt.Sprintf(`{"id": 9054, "ui_version": "v1.%d"}`, i))
+		write(fmt.Sprintf("%d.json", 9100+i), fmt.Sprintf(`{"id": %d}`, 9100+i))
+	}
+	wg.Wait()
+
+	// A returned snapshot is never modified by later refreshes
+	if len(first.paths) != 1 || len(first.Configs) != 1 || first.Configs[0].UIVersion != "v1" {
+		t.Errorf("expected the first snapshot to be unchanged, got %d paths", len(first.paths))
+	}
+
+	index, err := provider.Refresh(ctx)
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if len(index.Configs) != 11 {
+		t.Errorf("expected 11 configs after refresh, got %d", len(index.Configs))
+	}
+}
+
+func TestCachedConfigProviderMatchesLocal(t *testing.T) {
+	root := filepath.Join("..", "..", "vendor", "configs")
+	if _, err := os.Stat(root); err != nil {
+		t.Skip("vendor configs are not available")
+	}
+
+	ctx := context.Background()
+	local, err := NewLocalConfigProvider(root).LoadConfigs(ctx, "evo")
+	if err != nil {
+		t.Fatalf("LoadConfigs failed: %v", err)
+	}
+	cached, err := NewCachedConfigProvider(root, 0).LoadConfigs(ctx, "evo")
+	if err != nil {
+		t.Fatalf("LoadConfigs failed: %v", err)
+	}
+
+	if len(local) != len(cached) {
+		t.Fatalf("expected %d configs, got %d", len(local), len(cached))
+	}
+	for i := range local {
+		if local[i].ID != cached[i].ID || local[i].SourcePath != cached[i].SourcePath {
+			t.Errorf("config %d: expected %d (%s), got %d (%s)",
+				i, local[i].ID, local[i].SourcePath, cached[i].ID, cached[i].SourcePath)
+		}
+	}
+}
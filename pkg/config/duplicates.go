@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DuplicateConfigID is a config ID declared by more than one file
+type DuplicateConfigID struct {
+	ConfigID int      `json:"config_id"`
+	Paths    []string `json:"paths"`
+}
+
+// String renders the duplicate with the files declaring it
+func (d DuplicateConfigID) String() string {
+	return fmt.Sprintf("config ID %d is declared by %d files: %s", d.ConfigID, len(d.Paths), strings.Join(d.Paths, ", "))
+}
+
+// FindDuplicateIDs returns the IDs declared by more than one config, in order of first appearance
+func FindDuplicateIDs(configs []*LenderConfig) []DuplicateConfigID {
+	paths := make(map[int][]string)
+	var order []int
+	for _, config := range configs {
+		if _, ok := paths[config.ID]; !ok {
+			order = append(order, config.ID)
+		}
+		paths[config.ID] = append(paths[config.ID], config.SourcePath)
+	}
+
+	var duplicates []DuplicateConfigID
+	for _, id := range order {
+		if len(paths[id]) > 1 {
+			duplicates = append(duplicates, DuplicateConfigID{ConfigID: id, Paths: paths[id]})
+		}
+	}
+	return duplicates
+}
+
+// AmbiguousConfigError is returned when a config lookup matches more than one file
+type AmbiguousConfigError struct {
+	ConfigID   int
+	LeadSource string
+	Candidates []string
+}
+
+func (e *AmbiguousConfigError) Error() string {
+	lookup := fmt.Sprintf("config %d", e.ConfigID)
+	if e.LeadSource != "" {
+		lookup += fmt.Sprintf(" with lead source %s", e.LeadSource)
+	}
+	return fmt.Sprintf("%s is ambiguous, %d files match: %s", lookup, len(e.Candidates), strings.Join(e.Candidates, ", "))
+}
+
+// selectConfig returns the single match of a lookup, or an *AmbiguousConfigError listing the candidates
+func selectConfig(configID int, leadSource string, matches []*LenderConfig) (*LenderConfig, error) {
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	}
+
+	candidates := make([]string, 0, len(matches))
+	for _, match := range matches {
+		candidates = append(candidates, match.SourcePath)
+	}
+	return nil, &AmbiguousConfigError{ConfigID: configID, LeadSource: leadSource, Candidates: candidates}
+}
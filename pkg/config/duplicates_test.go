@@ -0,0 +1,94 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDuplicateConfigIDs(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		file := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("evo/9054_organic.json", `{"id": 9054, "name": "collect", "tags": [{"name": "lead_source", "value": "organic"}]}`)
+	write("evo/9054_paid.json", `{"id": 9054, "name": "collect", "tags": [{"name": "lead_source", "value": "paid"}]}`)
+	write("win/9054_organic.json", `{"id": 9054, "name": "collect.win", "tags": [{"name": "lead_source", "value": "organic"}]}`)
+	write("win/9012.json", `{"id": 9012, "name": "other"}`)
+	write("win/archive/9012.json", `{"id": 9012, "name": "other"}`)
+	write("win/collect_legacy.json", `{"id": 9300, "name": "collect.legacy"}`)
+
+	providers := map[string]ConfigProvider{
+		"local":  NewLocalConfigProvider(root),
+		"cached": NewCachedConfigProvider(root, 2),
+	}
+
+	for name, provider := range providers {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// Duplicates across files and folders are reported
+			_, report, err := LoadConfigsWithReport(ctx, provider, "", LoadLenient)
+			if err != nil {
+				t.Fatalf("lenient load failed: %v", err)
+			}
+			if len(report.Duplicates) != 1 || report.Duplicates[0].ConfigID != 9054 || len(report.Duplicates[0].Paths) != 3 {
+				t.Fatalf("expected config 9054 declared by 3 files, got %+v", report.Duplicates)
+			}
+			if report.OK() {
+				t.Error("expected a report with duplicates not to be OK")
+			}
+
+			_, _, err = LoadConfigsWithReport(ctx, provider, "", LoadStrict)
+			if err == nil || !strings.Contains(err.Error(), "1 config IDs are duplicated") {
+				t.Errorf("expected strict load to fail on duplicates, got %v", err)
+			}
+
+			// A folder without duplicates is fine
+			if _, _, err := LoadConfigsWithReport(ctx, provider, "win", LoadStrict); err != nil {
+				t.Errorf("expected win to load in strict mode: %v", err)
+			}
+
+			// The lead source narrows the lookup down to evo and win, which is still ambiguous
+			_, err = provider.LoadConfig(ctx, 9054, "organic")
+			var ambiguous *AmbiguousConfigError
+			if !errors.As(err, &ambiguous) {
+				t.Fatalf("expected an *AmbiguousConfigError, got %v", err)
+			}
+			if len(ambiguous.Candidates) != 2 || ambiguous.LeadSource != "organic" {
+				t.Errorf("expected 2 candidates for lead source organic, got %+v", ambiguous)
+			}
+
+			cfg, err := provider.LoadConfig(ctx, 9054, "paid")
+			if err != nil || filepath.Base(cfg.SourcePath) != "9054_paid.json" {
+				t.Errorf("expected the paid config, got %v (%v)", cfg, err)
+			}
+
+			// The id inside the file decides, not the file name
+			if cfg, err := provider.LoadConfig(ctx, 9300, ""); err != nil || filepath.Base(cfg.SourcePath) != "collect_legacy.json" {
+				t.Errorf("expected win/collect_legacy.json, got %v (%v)", cfg, err)
+			}
+
+			// Archived copies are not candidates
+			if cfg, err := provider.LoadConfig(ctx, 9012, ""); err != nil || filepath.Base(filepath.Dir(cfg.SourcePath)) != "win" {
+				t.Errorf("expected win/9012.json, got %v (%v)", cfg, err)
+			}
+
+			// 905 is contained in the file names of 9054 but is not their ID
+			if _, err := provider.LoadConfig(ctx, 905, ""); err == nil || errors.As(err, &ambiguous) {
+				t.Errorf("expected config 905 not to be found, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Location of the lender configs inside the digital_journey repository
+const (
+	DigitalJourneyRepoPath = "scripts/submodules/digital_journey"
+	LenderConfigsRepoPath  = "migration/sync/vietnam/tpbank/lender_configs"
+)
+
+// GitConfigProvider - load configs từ một git repository tại một ref (branch, tag, commit)
+// mà không cần checkout. Requires the git command line.
+type GitConfigProvider struct {
+	RepoPath string
+	Ref      string
+	BasePath string
+
+	// commit is Ref resolved once so every read sees the same snapshot
+	commit string
+}
+
+// NewGitConfigProvider tạo git provider đọc BasePath (relative to the repository root) tại ref
+func NewGitConfigProvider(ctx context.Context, repoPath, ref, basePath string) (*GitConfigProvider, error) {
+	p := &GitConfigProvider{
+		RepoPath: repoPath,
+		Ref:      ref,
+		BasePath: strings.Trim(path.Clean("/"+basePath), "/"),
+	}
+
+	out, err := p.git(ctx, nil, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve git ref %q in %s: %w", ref, repoPath, err)
+	}
+	p.commit = strings.TrimSpace(string(out))
+
+	return p, nil
+}
+
+// Commit returns the commit the provider reads from
+func (p *GitConfigProvider) Commit() string {
+	return p.commit
+}
+
+// GitCommit is a commit of the config repository
+type GitCommit struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c GitCommit) ShortHash() string {
+	if len(c.Hash) > 10 {
+		return c.Hash[:10]
+	}
+	return c.Hash
+}
+
+// ConfigCommits lists, oldest first, the commits up to the provider's ref that touched a
+// config file whose name contains configID
+func (p *GitConfigProvider) ConfigCommits(ctx context.Context, configID int) ([]GitCommit, error) {
+	pathspec := fmt.Sprintf(":(glob)**/*%d*.json", configID)
+	if p.BasePath != "" {
+		pathspec = fmt.Sprintf(":(glob)%s/**/*%d*.json", p.BasePath, configID)
+	}
+
+	out, err := p.git(ctx, nil, "log", "--reverse", "--format=%H%x1f%an%x1f%aI%x1f%s%x1e", p.commit, "--", pathspec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits of config %d: %w", configID, err)
+	}
+
+	var commits []GitCommit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected commit date %q: %w", fields[2], err)
+		}
+		commits = append(commits, GitCommit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    date,
+			Subject: fields[3],
+		})
+	}
+	return commits, nil
+}
+
+// AtCommit returns a provider reading the same repository and base path at another commit
+func (p *GitConfigProvider) AtCommit(commit GitCommit) *GitConfigProvider {
+	return &GitConfigProvider{
+		RepoPath: p.RepoPath,
+		Ref:      commit.ShortHash(),
+		BasePath: p.BasePath,
+		commit:   commit.Hash,
+	}
+}
+
+// LoadConfigs từ git tree tại ref; files that cannot be loaded are skipped
+func (p *GitConfigProvider) LoadConfigs(ctx context.Context, dir string) ([]*LenderConfig, error) {
+	configs, _, err := p.LoadConfigsWithReport(ctx, dir)
+	return configs, err
+}
+
+// LoadConfigsWithReport từ git tree tại ref, kèm diagnostics của các files không load được
+func (p *GitConfigProvider) LoadConfigsWithReport(ctx context.Context, dir string) ([]*LenderConfig, *LoadReport, error) {
+	files, err := p.listConfigFiles(ctx, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var configs []*LenderConfig
+	report := &LoadReport{Files: len(files)}
+	read := make(map[string]bool)
+	err = p.readFiles(ctx, files, func(file string, data []byte) {
+		read[file] = true
+		config, err := parseConfig(data, p.sourcePath(file))
+		if err != nil {
+			report.add(newLoadDiagnostic(p.sourcePath(file), data, err))
+			return
+		}
+		configs = append(configs, config)
+		report.Loaded++
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, file := range files {
+		if !read[file] {
+			report.add(LoadDiagnostic{Path: p.sourcePath(file), Reason: "not a blob"})
+		}
+	}
+
+	report.Duplicates = FindDuplicateIDs(configs)
+	return configs, report, nil
+}
+
+// LoadConfig từ git tree tại ref; returns an *AmbiguousConfigError when several files match
+func (p *GitConfigProvider) LoadConfig(ctx context.Context, configID int, leadSource string) (*LenderConfig, error) {
+	files, err := p.listConfigFiles(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// The id inside the file decides, not the file name
+	var matches []*LenderConfig
+	err = p.readFiles(ctx, files, func(file string, data []byte) {
+		config, err := parseConfig(data, p.sourcePath(file))
+		if err == nil && config != nil && config.ID == configID && hasLeadSource(config, leadSource) {
+			matches = append(matches, config)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	foundConfig, err := selectConfig(configID, leadSource, matches)
+	if err != nil {
+		return nil, err
+	}
+
+	if foundConfig == nil {
+		return nil, fmt.Errorf("config %d not found at %s", configID, p.Ref)
+	}
+
+	return foundConfig, nil
+}
+
+// listConfigFiles lists the JSON files under BasePath/dir, skipping archive directories
+func (p *GitConfigProvider) listConfigFiles(ctx context.Context, dir string) ([]string, error) {
+	root := strings.Trim(path.Join(p.BasePath, dir), "/")
+
+	args := []string{"ls-tree", "-r", "--name-only", "-z", p.commit}
+	if root != "" && root != "." {
+		args = append(args, "--", root)
+	}
+
+	out, err := p.git(ctx, nil, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configs from %s at %s: %w", root, p.Ref, err)
+	}
+
+	var files []string
+	for _, file := range strings.Split(string(out), "\x00") {
+		if !strings.HasSuffix(file, ".json") || isArchivePath(file) {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// readFiles reads the blobs of files at the commit with a single git cat-file process
+func (p *GitConfigProvider) readFiles(ctx context.Context, files []string, fn func(file string, data []byte)) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	var input bytes.Buffer
+	for _, file := range files {
+		input.WriteString(p.commit + ":" + file + "\n")
+	}
+
+	out, err := p.git(ctx, &input, "cat-file", "--batch")
+	if err != nil {
+		return fmt.Errorf("failed to read configs at %s: %w", p.Ref, err)
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(out))
+	for _, file := range files {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read git object of %s: %w", file, err)
+		}
+
+		// "<sha> blob <size>" or "<object> missing"
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			continue
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return fmt.Errorf("unexpected git cat-file header %q", strings.TrimSpace(header))
+		}
+
+		data := make([]byte, size+1) // content followed by a newline
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return fmt.Errorf("failed to read git object of %s: %w", file, err)
+		}
+		fn(file, data[:size])
+	}
+
+	return nil
+}
+
+// sourcePath identifies a file at the provider's ref, e.g. "v1.2.3:lender_configs/evo/9054.json"
+func (p *GitConfigProvider) sourcePath(file string) string {
+	return p.Ref + ":" + file
+}
+
+func (p *GitConfigProvider) git(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", p.RepoPath}, args...)...)
+	cmd.Stdin = stdin
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// parseConfig decodes a lender config file
+func parseConfig(data []byte, sourcePath string) (*LenderConfig, error) {
+	var config LenderConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	config.SourcePath = sourcePath
+
+	return &config, nil
+}
+
+// hasLeadSource reports whether the config is tagged with leadSource; an empty leadSource matches any config
+func hasLeadSource(config *LenderConfig, leadSource string) bool {
+	if leadSource == "" {
+		return true
+	}
+	for _, tag := range config.Tags {
+		if tag.Name == "lead_source" && tag.Value == leadSource {
+			return true
+		}
+	}
+	return false
+}
+
+// isArchivePath reports whether a slash separated path is inside an archive directory
+func isArchivePath(file string) bool {
+	dirs := strings.Split(path.Dir(file), "/")
+	for _, dir := range dirs {
+		if strings.Contains(strings.ToLower(dir), "archive") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitConfigProvider(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		file := filepath.Join(repo, "lender_configs", name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("evo/9054_organic.json", `{"id": 9054, "name": "collect", "ui_version": "v9.1.5.0", "tags": [{"name": "lead_source", "value": "organic"}]}`)
+	write("evo/archive/9001.json", `{"id": 9001}`)
+	write("evo/README.md", "not a config")
+	run("add", "-A")
+	run("commit", "-q", "-m", "first")
+	run("tag", "v1")
+
+	write("evo/9054_organic.json", `{"id": 9054, "name": "collect", "ui_version": "v9.1.6.0", "tags": [{"name": "lead_source", "value": "organic"}]}`)
+	write("evo/9055_paid.json", `{"id": 9055, "name": "collect.paid", "ui_version": "v9.1.6.0"}`)
+	write("evo/collect_legacy.json", `{"id": 9300, "name": "collect.legacy", "ui_version": "v9.1.6.0"}`)
+	run("add", "-A")
+	run("commit", "-q", "-m", "second")
+
+	ctx := context.Background()
+	provider, err := NewGitConfigProvider(ctx, repo, "v1", "lender_configs")
+	if err != nil {
+		t.Fatalf("NewGitConfigProvider failed: %v", err)
+	}
+
+	// The working tree is at the second commit, the provider still reads v1
+	configs, err := provider.LoadConfigs(ctx, "evo")
+	if err != nil {
+		t.Fatalf("LoadConfigs failed: %v", err)
+	}
+	if len(configs) != 1 || configs[0].UIVersion != "v9.1.5.0" {
+		t.Fatalf("expected only config 9054 at v9.1.5.0, got %+v", configs)
+	}
+	if configs[0].SourcePath != "v1:lender_configs/evo/9054_organic.json" {
+		t.Errorf("unexpected source path %s", configs[0].SourcePath)
+	}
+
+	if _, err := provider.LoadConfig(ctx, 9054, "paid"); err == nil {
+		t.Error("expected config 9054 not to match lead source paid")
+	}
+
+	head, err := NewGitConfigProvider(ctx, repo, "HEAD", "lender_configs")
+	if err != nil {
+		t.Fatalf("NewGitConfigProvider failed: %v", err)
+	}
+	cfg, err := head.LoadConfig(ctx, 9054, "organic")
+	if err != nil || cfg.UIVersion != "v9.1.6.0" {
+		t.Errorf("expected config 9054 at v9.1.6.0 on HEAD, got %+v, %v", cfg, err)
+	}
+
+	// Files are matched by their id, whatever their name, outside archive directories
+	if cfg, err := head.LoadConfig(ctx, 9300, ""); err != nil || cfg.SourcePath != "HEAD:lender_configs/evo/collect_legacy.json" {
+		t.Errorf("expected config 9300 from evo/collect_legacy.json, got %+v, %v", cfg, err)
+	}
+	if _, err := head.LoadConfig(ctx, 9001, ""); err == nil {
+		t.Error("expected the archived config not to be found")
+	}
+
+	if _, err := NewGitConfigProvider(ctx, repo, "does-not-exist", "lender_configs"); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LoadMode decides what happens when config files cannot be loaded
+type LoadMode string
+
+// Load modes
+const (
+	// LoadLenient skips the files that cannot be loaded and reports them as warnings
+	LoadLenient LoadMode = "lenient"
+	// LoadStrict fails the load when any file cannot be loaded
+	LoadStrict LoadMode = "strict"
+)
+
+// ParseLoadMode parses "lenient" or "strict"
+func ParseLoadMode(s string) (LoadMode, error) {
+	switch mode := LoadMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case LoadLenient, LoadStrict:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown load mode %q (want %s or %s)", s, LoadLenient, LoadStrict)
+	}
+}
+
+// LoadDiagnostic is a config file that could not be loaded. Line and Column (1-based)
+// locate JSON errors and are 0 when unknown.
+type LoadDiagnostic struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// String renders the diagnostic as "path:line:column: reason"
+func (d LoadDiagnostic) String() string {
+	if d.Line > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s", d.Path, d.Line, d.Column, d.Reason)
+	}
+	return fmt.Sprintf("%s: %s", d.Path, d.Reason)
+}
+
+// LoadReport lists the config files seen by a load, the ones that failed and the config
+// IDs declared by more than one loaded file
+type LoadReport struct {
+	Files       int                 `json:"files"`
+	Loaded      int                 `json:"loaded"`
+	Diagnostics []LoadDiagnostic    `json:"diagnostics"`
+	Duplicates  []DuplicateConfigID `json:"duplicates"`
+}
+
+// OK reports whether every file was loaded and every config ID is unique
+func (r *LoadReport) OK() bool {
+	return len(r.Diagnostics) == 0 && len(r.Duplicates) == 0
+}
+
+// add records a diagnostic
+func (r *LoadReport) add(d LoadDiagnostic) {
+	r.Diagnostics = append(r.Diagnostics, d)
+}
+
+// LoadError is returned by strict loads when config files could not be loaded or config
+// IDs are duplicated
+type LoadError struct {
+	Report *LoadReport
+}
+
+func (e *LoadError) Error() string {
+	var problems []string
+	if len(e.Report.Diagnostics) > 0 {
+		problems = append(problems, fmt.Sprintf("%d of %d config files could not be loaded", len(e.Report.Diagnostics), e.Report.Files))
+	}
+	if len(e.Report.Duplicates) > 0 {
+		problems = append(problems, fmt.Sprintf("%d config IDs are duplicated", len(e.Report.Duplicates)))
+	}
+
+	var out strings.Builder
+	out.WriteString(strings.Join(problems, ", "))
+	for _, d := range e.Report.Diagnostics {
+		out.WriteString("\n  " + d.String())
+	}
+	for _, d := range e.Report.Duplicates {
+		out.WriteString("\n  " + d.String())
+	}
+	return out.String()
+}
+
+// ReportingConfigProvider là provider trả về LoadReport cùng với configs
+type ReportingConfigProvider interface {
+	ConfigProvider
+	LoadConfigsWithReport(ctx context.Context, path string) ([]*LenderConfig, *LoadReport, error)
+}
+
+// LoadConfigsWithReport load configs từ provider kèm diagnostics; providers that do not
+// report diagnostics get an empty report. In strict mode a report with diagnostics is
+// returned as a *LoadError.
+func LoadConfigsWithReport(ctx context.Context, provider ConfigProvider, path string, mode LoadMode) ([]*LenderConfig, *LoadReport, error) {
+	var configs []*LenderConfig
+	var report *LoadReport
+	var err error
+
+	if reporting, ok := provider.(ReportingConfigProvider); ok {
+		configs, report, err = reporting.LoadConfigsWithReport(ctx, path)
+	} else {
+		configs, err = provider.LoadConfigs(ctx, path)
+		report = &LoadReport{Files: len(configs), Loaded: len(configs), Duplicates: FindDuplicateIDs(configs)}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if mode == LoadStrict && !report.OK() {
+		return nil, report, &LoadError{Report: report}
+	}
+	return configs, report, nil
+}
+
+// newLoadDiagnostic describes why a file failed to read or decode, locating JSON errors in data
+func newLoadDiagnostic(path string, data []byte, err error) LoadDiagnostic {
+	d := LoadDiagnostic{Path: path, Reason: err.Error()}
+
+	var offset int64 = -1
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+		d.Reason = "invalid JSON: " + syntaxErr.Error()
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	}
+
+	if offset >= 0 && offset <= int64(len(data)) {
+		before := data[:offset]
+		d.Line = bytes.Count(before, []byte("\n")) + 1
+		d.Column = max(len(before)-bytes.LastIndexByte(before, '\n')-1, 1)
+	}
+	return d
+}
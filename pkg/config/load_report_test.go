@@ -0,0 +1,90 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigsWithReport(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		file := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("evo/9054.json", `{"id": 9054, "name": "collect"}`)
+	write("evo/9055.json", "{\n  \"id\": 9055,\n  \"name\": \"collect\",\n}\n")
+	write("evo/9056.json", "{\n  \"id\": \"9056\"\n}")
+
+	providers := map[string]ConfigProvider{
+		"local":  NewLocalConfigProvider(root),
+		"cached": NewCachedConfigProvider(root, 2),
+	}
+
+	for name, provider := range providers {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			configs, report, err := LoadConfigsWithReport(ctx, provider, "evo", LoadLenient)
+			if err != nil {
+				t.Fatalf("lenient load failed: %v", err)
+			}
+			if len(configs) != 1 || configs[0].ID != 9054 {
+				t.Fatalf("expected only config 9054, got %+v", configs)
+			}
+			if report.Files != 3 || report.Loaded != 1 || len(report.Diagnostics) != 2 {
+				t.Fatalf("unexpected report %+v", report)
+			}
+
+			syntax := report.Diagnostics[0]
+			if filepath.Base(syntax.Path) != "9055.json" || syntax.Line != 4 || syntax.Column != 1 {
+				t.Errorf("expected the syntax error at 9055.json:4:1, got %s", syntax)
+			}
+			if !strings.HasPrefix(syntax.Reason, "invalid JSON") {
+				t.Errorf("unexpected reason %q", syntax.Reason)
+			}
+
+			typed := report.Diagnostics[1]
+			if filepath.Base(typed.Path) != "9056.json" || typed.Line != 2 {
+				t.Errorf("expected the type error on line 2 of 9056.json, got %s", typed)
+			}
+
+			_, _, err = LoadConfigsWithReport(ctx, provider, "evo", LoadStrict)
+			var loadErr *LoadError
+			if !errors.As(err, &loadErr) {
+				t.Fatalf("expected a *LoadError in strict mode, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "2 of 3 config files could not be loaded") {
+				t.Errorf("unexpected error %q", err)
+			}
+
+			// LoadConfigs keeps skipping the broken files
+			configs, err = provider.LoadConfigs(ctx, "evo")
+			if err != nil || len(configs) != 1 {
+				t.Errorf("expected LoadConfigs to return 1 config, got %d (%v)", len(configs), err)
+			}
+		})
+	}
+}
+
+func TestParseLoadMode(t *testing.T) {
+	for input, want := range map[string]LoadMode{"lenient": LoadLenient, " Strict ": LoadStrict} {
+		got, err := ParseLoadMode(input)
+		if err != nil || got != want {
+			t.Errorf("ParseLoadMode(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseLoadMode("loose"); err == nil {
+		t.Error("expected an error for an unknown load mode")
+	}
+}
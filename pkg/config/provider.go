@@ -2,7 +2,6 @@ package config
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -27,15 +26,23 @@ func NewLocalConfigProvider(basePath string) *LocalConfigProvider {
 	}
 }
 
-// LoadConfigs từ local filesystem
+// LoadConfigs từ local filesystem; files that cannot be loaded are skipped
 func (p *LocalConfigProvider) LoadConfigs(ctx context.Context, path string) ([]*LenderConfig, error) {
+	configs, _, err := p.LoadConfigsWithReport(ctx, path)
+	return configs, err
+}
+
+// LoadConfigsWithReport từ local filesystem, kèm diagnostics của các files không load được
+func (p *LocalConfigProvider) LoadConfigsWithReport(ctx context.Context, path string) ([]*LenderConfig, *LoadReport, error) {
 	var configs []*LenderConfig
+	report := &LoadReport{}
 
 	fullPath := filepath.Join(p.BasePath, path)
 
 	err := filepath.Walk(fullPath, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
-			return nil // Skip errors
+			report.add(LoadDiagnostic{Path: filePath, Reason: err.Error()})
+			return nil
 		}
 
 		// Skip archive directories
@@ -45,50 +52,54 @@ func (p *LocalConfigProvider) LoadConfigs(ctx context.Context, path string) ([]*
 
 		// Process JSON files
 		if !info.IsDir() && strings.HasSuffix(info.Name(), ".json") {
-			config, err := p.loadConfigFile(filePath)
-			if err == nil && config != nil {
-				configs = append(configs, config)
+			report.Files++
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				report.add(newLoadDiagnostic(filePath, nil, err))
+				return nil
+			}
+			config, err := parseConfig(data, filePath)
+			if err != nil {
+				report.add(newLoadDiagnostic(filePath, data, err))
+				return nil
 			}
+			configs = append(configs, config)
+			report.Loaded++
 		}
 
 		return nil
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to scan configs from %s: %w", fullPath, err)
+		return nil, nil, fmt.Errorf("failed to scan configs from %s: %w", fullPath, err)
 	}
 
-	return configs, nil
+	report.Duplicates = FindDuplicateIDs(configs)
+	return configs, report, nil
 }
 
-// LoadConfig từ local filesystem
+// LoadConfig từ local filesystem; every JSON file outside archive directories whose id is
+// configID is a candidate, whatever its name, and several matches are an *AmbiguousConfigError
 func (p *LocalConfigProvider) LoadConfig(ctx context.Context, configID int, leadSource string) (*LenderConfig, error) {
-	// Search pattern: *{configID}*.json
-	var foundConfig *LenderConfig
+	var matches []*LenderConfig
 
 	err := filepath.Walk(p.BasePath, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
 
-		if !info.IsDir() && strings.Contains(info.Name(), fmt.Sprintf("%d", configID)) && strings.HasSuffix(info.Name(), ".json") {
+		// Skip archive directories
+		if info.IsDir() && strings.Contains(strings.ToLower(info.Name()), "archive") {
+			return filepath.SkipDir
+		}
+
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".json") {
 			config, err := p.loadConfigFile(filePath)
 			if err == nil && config != nil && config.ID == configID {
 				// Check lead source if specified
-				if leadSource != "" {
-					hasLeadSource := false
-					for _, tag := range config.Tags {
-						if tag.Name == "lead_source" && tag.Value == leadSource {
-							hasLeadSource = true
-							break
-						}
-					}
-					if !hasLeadSource {
-						return nil // Continue searching
-					}
+				if hasLeadSource(config, leadSource) {
+					matches = append(matches, config)
 				}
-				foundConfig = config
-				return filepath.SkipAll // Found, stop searching
 			}
 		}
 		return nil
@@ -98,6 +109,10 @@ func (p *LocalConfigProvider) LoadConfig(ctx context.Context, configID int, lead
 		return nil, fmt.Errorf("failed to search for config %d: %w", configID, err)
 	}
 
+	foundConfig, err := selectConfig(configID, leadSource, matches)
+	if err != nil {
+		return nil, err
+	}
 	if foundConfig == nil {
 		return nil, fmt.Errorf("config %d not found", configID)
 	}
@@ -111,26 +126,21 @@ func (p *LocalConfigProvider) loadConfigFile(filePath string) (*LenderConfig, er
 		return nil, err
 	}
 
-	var config LenderConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, err
-	}
-
-	return &config, nil
+	return parseConfig(data, filePath)
 }
 
-// GetConfigProvider tạo provider dựa trên environment - chỉ sử dụng local files
+// GetConfigProvider tạo provider dựa trên environment - chỉ sử dụng local files, cached in memory
 func GetConfigProvider() ConfigProvider {
 	// Check if vendor configs exist (preferred)
 	if _, err := os.Stat("vendor/configs"); err == nil {
-		return NewLocalConfigProvider("vendor/configs")
+		return NewCachedConfigProvider("vendor/configs", 0)
 	}
 
 	// Check if submodules exist
-	if _, err := os.Stat("scripts/submodules/digital_journey"); err == nil {
-		return NewLocalConfigProvider("scripts/submodules/digital_journey/migration/sync/vietnam/tpbank/lender_configs")
+	if _, err := os.Stat(DigitalJourneyRepoPath); err == nil {
+		return NewCachedConfigProvider(filepath.Join(DigitalJourneyRepoPath, LenderConfigsRepoPath), 0)
 	}
 
 	// Default to vendor structure
-	return NewLocalConfigProvider("vendor/configs")
+	return NewCachedConfigProvider("vendor/configs", 0)
 }
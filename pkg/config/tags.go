@@ -0,0 +1,20 @@
+package config
+
+// GetFlowTypeFromTags gets flow_type from tags (prioritizes esign_flow_type first, then flow_type)
+func GetFlowTypeFromTags(tags []Tag) string {
+	// Prioritize esign_flow_type first
+	for _, tag := range tags {
+		if tag.Name == "esign_flow_type" {
+			return tag.Value
+		}
+	}
+
+	// If no esign_flow_type, find flow_type
+	for _, tag := range tags {
+		if tag.Name == "flow_type" {
+			return tag.Value
+		}
+	}
+
+	return "unknown"
+}
@@ -26,6 +26,9 @@ type LenderConfig struct {
 	UIFlowSettings  map[string]interface{}    `json:"ui_flow_settings"`
 	DecisionEngines map[string]DecisionEngine `json:"decision_engines,omitempty"`
 	Weight          int                       `json:"weight"`
+
+	// SourcePath is the file the config was loaded from, if any
+	SourcePath string `json:"-"`
 }
 
 // ConfigInfo represents processed configuration information
@@ -0,0 +1,429 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UIFlowSettingsSchemaVersion is the latest ui_flow_settings schema understood by the decoder
+const UIFlowSettingsSchemaVersion = 1
+
+// Reserved keys of ui_flow_settings; every other top-level key names a ui_flow step
+const (
+	SettingsKeySchemaVersion            = "schema_version"
+	SettingsKeyFeatureFlags             = "feature_flags"
+	SettingsKeySubUIVersion             = "sub_ui_version"
+	SettingsKeySubUIVersionByConditions = "sub_ui_version_by_conditions"
+	SettingsKeyCondition                = "condition"
+)
+
+// Kinds of settings issues reported by the decoder
+const (
+	SettingsIssueUnknownKey         = "unknown_key"
+	SettingsIssueWrongType          = "wrong_type"
+	SettingsIssueUnsupportedVersion = "unsupported_schema_version"
+)
+
+// UIFlowSettings is the typed model of a lender config's ui_flow_settings.
+// SchemaVersion is 0 when the settings do not declare one, which is read as version 1.
+type UIFlowSettings struct {
+	SchemaVersion int
+	FeatureFlags  map[string]bool
+	Steps         map[string]StepSettings
+	// Extra keeps top-level fields the decoder did not understand so they survive a round trip
+	Extra map[string]interface{}
+}
+
+// StepSettings holds the settings of a single ui_flow step
+type StepSettings struct {
+	SubUIVersion             string
+	SubUIVersionByConditions []SubUIVersionCondition
+	// InvalidConditions keeps the sub_ui_version_by_conditions entries that could not be
+	// decoded; they are written back after the decoded ones
+	InvalidConditions []interface{}
+	FeatureFlags      map[string]bool
+	// Extra keeps step fields the decoder did not understand so they survive a round trip
+	Extra map[string]interface{}
+}
+
+// SubUIVersionCondition is a conditional sub UI version override of a step
+type SubUIVersionCondition struct {
+	Condition    string `json:"condition"`
+	SubUIVersion string `json:"sub_ui_version"`
+	// Extra keeps unknown fields of the entry so they survive a round trip
+	Extra map[string]interface{} `json:"-"`
+}
+
+// SettingsIssue describes an unknown key or a wrongly typed value found while decoding
+type SettingsIssue struct {
+	File    string `json:"file,omitempty"`
+	Path    string `json:"path"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+func (i SettingsIssue) Error() string {
+	if i.File == "" {
+		return fmt.Sprintf("%s: %s", i.Path, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.File, i.Path, i.Message)
+}
+
+// settingsDecoder walks the generic JSON value of ui_flow_settings and collects issues
+type settingsDecoder struct {
+	file   string
+	issues []SettingsIssue
+}
+
+// DecodeUIFlowSettings converts the untyped ui_flow_settings of a config into the typed
+// model. Unknown keys and wrongly typed values are reported as issues (with the JSON path
+// and file name) and kept in Extra, or in InvalidConditions for entries of
+// sub_ui_version_by_conditions; an error is returned only for an unsupported schema version.
+func DecodeUIFlowSettings(raw map[string]interface{}, file string) (*UIFlowSettings, []SettingsIssue, error) {
+	d := &settingsDecoder{file: file}
+	settings := &UIFlowSettings{
+		Steps: make(map[string]StepSettings),
+	}
+
+	for _, key := range sortedKeys(raw) {
+		value := raw[key]
+		path := jsonPath("$", key)
+
+		switch key {
+		case SettingsKeySchemaVersion:
+			version, ok := value.(float64)
+			if !ok || version != float64(int(version)) {
+				d.wrongType(path, "integer", value)
+				settings.addExtra(key, value)
+				continue
+			}
+			if int(version) > UIFlowSettingsSchemaVersion || int(version) < 1 {
+				return nil, d.issues, fmt.Errorf("%s: unsupported ui_flow_settings schema_version %d (supported: %d)",
+					d.location(path), int(version), UIFlowSettingsSchemaVersion)
+			}
+			settings.SchemaVersion = int(version)
+		case SettingsKeyFeatureFlags:
+			flags, ok := d.decodeFlags(path, value)
+			if !ok {
+				settings.addExtra(key, value)
+				continue
+			}
+			settings.FeatureFlags = flags
+		default:
+			stepValue, ok := value.(map[string]interface{})
+			if !ok {
+				d.issue(path, SettingsIssueUnknownKey, fmt.Sprintf("unknown key %q (step settings must be an object, got %s)", key, jsonTypeName(value)))
+				settings.addExtra(key, value)
+				continue
+			}
+			settings.Steps[key] = d.decodeStep(path, stepValue)
+		}
+	}
+
+	return settings, d.issues, nil
+}
+
+// ParseUIFlowSettings decodes ui_flow_settings from raw JSON
+func ParseUIFlowSettings(data []byte, file string) (*UIFlowSettings, []SettingsIssue, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal ui_flow_settings from %s: %w", file, err)
+	}
+	return DecodeUIFlowSettings(raw, file)
+}
+
+// TypedUIFlowSettings decodes the config's ui_flow_settings into the typed model
+func (c *LenderConfig) TypedUIFlowSettings() (*UIFlowSettings, []SettingsIssue, error) {
+	return DecodeUIFlowSettings(c.UIFlowSettings, c.SourcePath)
+}
+
+// Step returns the settings of a step, if declared
+func (s *UIFlowSettings) Step(name string) (StepSettings, bool) {
+	if s == nil {
+		return StepSettings{}, false
+	}
+	step, ok := s.Steps[name]
+	return step, ok
+}
+
+// ToMap converts the typed settings back to the untyped form stored on LenderConfig
+func (s *UIFlowSettings) ToMap() map[string]interface{} {
+	out := make(map[string]interface{})
+	for key, value := range s.Extra {
+		out[key] = value
+	}
+
+	if s.SchemaVersion != 0 {
+		out[SettingsKeySchemaVersion] = float64(s.SchemaVersion)
+	}
+	if len(s.FeatureFlags) > 0 {
+		out[SettingsKeyFeatureFlags] = flagsToMap(s.FeatureFlags)
+	}
+	for name, step := range s.Steps {
+		out[name] = step.toMap()
+	}
+
+	return out
+}
+
+// MarshalJSON encodes the settings in the ui_flow_settings JSON layout, including unknown fields
+func (s *UIFlowSettings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToMap())
+}
+
+// UnmarshalJSON decodes settings leniently; use ParseUIFlowSettings to get the issues
+func (s *UIFlowSettings) UnmarshalJSON(data []byte) error {
+	decoded, _, err := ParseUIFlowSettings(data, "")
+	if err != nil {
+		return err
+	}
+	*s = *decoded
+	return nil
+}
+
+func (s StepSettings) toMap() map[string]interface{} {
+	out := make(map[string]interface{})
+	for key, value := range s.Extra {
+		out[key] = value
+	}
+	if s.SubUIVersion != "" {
+		out[SettingsKeySubUIVersion] = s.SubUIVersion
+	}
+	if len(s.SubUIVersionByConditions) > 0 || len(s.InvalidConditions) > 0 {
+		conditions := make([]interface{}, 0, len(s.SubUIVersionByConditions)+len(s.InvalidConditions))
+		for _, condition := range s.SubUIVersionByConditions {
+			entry := make(map[string]interface{})
+			for key, value := range condition.Extra {
+				entry[key] = value
+			}
+			entry[SettingsKeyCondition] = condition.Condition
+			entry[SettingsKeySubUIVersion] = condition.SubUIVersion
+			conditions = append(conditions, entry)
+		}
+		out[SettingsKeySubUIVersionByConditions] = append(conditions, s.InvalidConditions...)
+	}
+	if len(s.FeatureFlags) > 0 {
+		out[SettingsKeyFeatureFlags] = flagsToMap(s.FeatureFlags)
+	}
+	return out
+}
+
+func flagsToMap(flags map[string]bool) map[string]interface{} {
+	out := make(map[string]interface{}, len(flags))
+	for key, enabled := range flags {
+		out[key] = enabled
+	}
+	return out
+}
+
+func (d *settingsDecoder) decodeStep(path string, raw map[string]interface{}) StepSettings {
+	var step StepSettings
+
+	for _, key := range sortedKeys(raw) {
+		value := raw[key]
+		keyPath := jsonPath(path, key)
+
+		switch key {
+		case SettingsKeySubUIVersion:
+			subUIVersion, ok := value.(string)
+			if !ok {
+				d.wrongType(keyPath, "string", value)
+				step.addExtra(key, value)
+				continue
+			}
+			step.SubUIVersion = subUIVersion
+		case SettingsKeySubUIVersionByConditions:
+			conditions, invalid, ok := d.decodeConditions(keyPath, value)
+			if !ok {
+				step.addExtra(key, value)
+				continue
+			}
+			step.SubUIVersionByConditions = conditions
+			step.InvalidConditions = invalid
+		case SettingsKeyFeatureFlags:
+			flags, ok := d.decodeFlags(keyPath, value)
+			if !ok {
+				step.addExtra(key, value)
+				continue
+			}
+			step.FeatureFlags = flags
+		default:
+			d.issue(keyPath, SettingsIssueUnknownKey, fmt.Sprintf("unknown key %q", key))
+			step.addExtra(key, value)
+		}
+	}
+
+	return step
+}
+
+// decodeConditions decodes the entries of sub_ui_version_by_conditions. An entry that is not
+// an object or has a wrongly typed field is reported and returned in invalid, the other
+// entries are still decoded; unknown fields are reported and kept on the entry.
+func (d *settingsDecoder) decodeConditions(path string, value interface{}) (conditions []SubUIVersionCondition, invalid []interface{}, ok bool) {
+	items, ok := value.([]interface{})
+	if !ok {
+		d.wrongType(path, "array", value)
+		return nil, nil, false
+	}
+
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			d.wrongType(itemPath, "object", item)
+			invalid = append(invalid, item)
+			continue
+		}
+
+		var condition SubUIVersionCondition
+		valid := true
+		for _, key := range sortedKeys(entry) {
+			keyPath := jsonPath(itemPath, key)
+			switch key {
+			case SettingsKeyCondition, SettingsKeySubUIVersion:
+				text, ok := entry[key].(string)
+				if !ok {
+					d.wrongType(keyPath, "string", entry[key])
+					valid = false
+					continue
+				}
+				if key == SettingsKeyCondition {
+					condition.Condition = text
+				} else {
+					condition.SubUIVersion = text
+				}
+			default:
+				d.issue(keyPath, SettingsIssueUnknownKey, fmt.Sprintf("unknown key %q", key))
+				if condition.Extra == nil {
+					condition.Extra = make(map[string]interface{})
+				}
+				condition.Extra[key] = entry[key]
+			}
+		}
+		if !valid {
+			invalid = append(invalid, item)
+			continue
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, invalid, true
+}
+
+// identifierKey matches the keys written in dot notation in JSON paths
+var identifierKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// jsonPath appends key to path, in bracket notation when the key is not a plain identifier:
+// step names contain dots, so $.app_form.personal_info would be ambiguous
+func jsonPath(path, key string) string {
+	if identifierKey.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+func (d *settingsDecoder) decodeFlags(path string, value interface{}) (map[string]bool, bool) {
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		d.wrongType(path, "object", value)
+		return nil, false
+	}
+
+	flags := make(map[string]bool)
+	valid := true
+	for _, key := range sortedKeys(raw) {
+		enabled, ok := raw[key].(bool)
+		if !ok {
+			d.wrongType(jsonPath(path, key), "boolean", raw[key])
+			valid = false
+			continue
+		}
+		flags[key] = enabled
+	}
+	return flags, valid
+}
+
+func (d *settingsDecoder) wrongType(path, expected string, value interface{}) {
+	d.issue(path, SettingsIssueWrongType, fmt.Sprintf("expected %s, got %s", expected, jsonTypeName(value)))
+}
+
+func (d *settingsDecoder) issue(path, kind, message string) {
+	d.issues = append(d.issues, SettingsIssue{
+		File:    d.file,
+		Path:    path,
+		Kind:    kind,
+		Message: message,
+	})
+}
+
+func (d *settingsDecoder) location(path string) string {
+	if d.file == "" {
+		return path
+	}
+	return d.file + ": " + path
+}
+
+func (s *UIFlowSettings) addExtra(key string, value interface{}) {
+	if s.Extra == nil {
+		s.Extra = make(map[string]interface{})
+	}
+	s.Extra[key] = value
+}
+
+func (s *StepSettings) addExtra(key string, value interface{}) {
+	if s.Extra == nil {
+		s.Extra = make(map[string]interface{})
+	}
+	s.Extra[key] = value
+}
+
+// jsonTypeName names the JSON type of a value decoded into interface{}
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64, json.Number, int:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return strings.TrimPrefix(fmt.Sprintf("%T", value), "*")
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateUIFlowSettings decodes the ui_flow_settings of every config and returns all issues found
+func ValidateUIFlowSettings(configs []*LenderConfig) []SettingsIssue {
+	var issues []SettingsIssue
+	for _, cfg := range configs {
+		_, cfgIssues, err := cfg.TypedUIFlowSettings()
+		issues = append(issues, cfgIssues...)
+		if err != nil {
+			issues = append(issues, SettingsIssue{
+				File:    cfg.SourcePath,
+				Path:    jsonPath("$", SettingsKeySchemaVersion),
+				Kind:    SettingsIssueUnsupportedVersion,
+				Message: err.Error(),
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseUIFlowSettingsReportsIssues(t *testing.T) {
+	data := []byte(`{
+		"feature_flags": {"nfc": true},
+		"esign.review": {
+			"sub_ui_version": 2,
+			"sub_ui_version_by_conditions": [{"condition": "lead_source=organic", "sub_ui_version": "v1.1"}],
+			"sub_ui_verison": "typo"
+		},
+		"legacy_mode": "on"
+	}`)
+
+	settings, issues, err := ParseUIFlowSettings(data, "evo/9054_organic.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Kind+" "+issue.Path)
+	}
+	want := []string{
+		`unknown_key $["esign.review"].sub_ui_verison`,
+		`wrong_type $["esign.review"].sub_ui_version`,
+		"unknown_key $.legacy_mode",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+	if !strings.HasPrefix(issues[1].Error(), `evo/9054_organic.json: $["esign.review"].sub_ui_version: expected string`) {
+		t.Errorf("issue message should carry file and path, got %q", issues[1].Error())
+	}
+
+	step, ok := settings.Step("esign.review")
+	if !ok || len(step.SubUIVersionByConditions) != 1 || !settings.FeatureFlags["nfc"] {
+		t.Fatalf("typed settings not decoded: %+v", settings)
+	}
+
+	// Unknown and invalid fields survive a round trip
+	roundTrip, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var original, encoded map[string]interface{}
+	_ = json.Unmarshal(data, &original)
+	_ = json.Unmarshal(roundTrip, &encoded)
+	if !reflect.DeepEqual(original, encoded) {
+		t.Errorf("round trip changed settings:\n got %s", roundTrip)
+	}
+}
+
+func TestParseUIFlowSettingsRejectsFutureSchema(t *testing.T) {
+	_, _, err := ParseUIFlowSettings([]byte(`{"schema_version": 99}`), "a.json")
+	if err == nil || !strings.Contains(err.Error(), "unsupported ui_flow_settings schema_version 99") {
+		t.Errorf("expected schema version error, got %v", err)
+	}
+}
+
+func TestParseUIFlowSettingsSkipsBadConditions(t *testing.T) {
+	data := []byte(`{
+		"esign.review": {
+			"sub_ui_version_by_conditions": [
+				{"condition": "telco_code=viettel", "sub_ui_version": "v1.1"},
+				{"condition": 1, "sub_ui_version": "v1.2"},
+				"telco_code=mobifone",
+				{"condition": "telco_code=vinaphone", "sub_ui_version": "v1.3", "note": "kept"}
+			]
+		}
+	}`)
+
+	settings, issues, err := ParseUIFlowSettings(data, "a.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Kind+" "+issue.Path)
+	}
+	want := []string{
+		`wrong_type $["esign.review"].sub_ui_version_by_conditions[1].condition`,
+		`wrong_type $["esign.review"].sub_ui_version_by_conditions[2]`,
+		`unknown_key $["esign.review"].sub_ui_version_by_conditions[3].note`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+
+	// Only the bad entries are skipped
+	step, _ := settings.Step("esign.review")
+	if len(step.SubUIVersionByConditions) != 2 || step.SubUIVersionByConditions[1].SubUIVersion != "v1.3" {
+		t.Errorf("expected the two valid conditions, got %+v", step.SubUIVersionByConditions)
+	}
+	if len(step.InvalidConditions) != 2 || step.Extra != nil {
+		t.Errorf("expected the bad entries in InvalidConditions, got %+v", step)
+	}
+
+	// Skipped entries and unknown fields are written back
+	encoded := step.toMap()[SettingsKeySubUIVersionByConditions].([]interface{})
+	if len(encoded) != 4 || encoded[1].(map[string]interface{})["note"] != "kept" {
+		t.Errorf("round trip lost entries: %v", encoded)
+	}
+}
+
+func TestValidateUIFlowSettingsUnsupportedSchema(t *testing.T) {
+	configs := []*LenderConfig{{SourcePath: "a.json", UIFlowSettings: map[string]interface{}{"schema_version": float64(2)}}}
+
+	issues := ValidateUIFlowSettings(configs)
+	if len(issues) != 1 || issues[0].Kind != SettingsIssueUnsupportedVersion || issues[0].Path != "$.schema_version" {
+		t.Errorf("expected one unsupported schema version issue, got %+v", issues)
+	}
+}
+
+func TestSettingsIssuePathsQuoteStepNames(t *testing.T) {
+	raw := map[string]interface{}{
+		"otp":                    map[string]interface{}{"sub_ui_version": float64(1)},
+		"app_form.personal_info": map[string]interface{}{"sub_ui_version": float64(1), "feature_flags": map[string]interface{}{"new-layout": "yes"}},
+	}
+
+	_, issues, err := DecodeUIFlowSettings(raw, "a.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Path)
+	}
+	want := []string{
+		`$["app_form.personal_info"].feature_flags["new-layout"]`,
+		`$["app_form.personal_info"].sub_ui_version`,
+		`$.otp.sub_ui_version`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("paths = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,164 @@
+package diagram
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+)
+
+// TopologyGraph builds the diagram of a config routing graph. Configs are boxes labelled with
+// their ID, name, ui_version and flow_type, colored by flow type and framed by the value of
+// the clusterBy tag (analyzer.ClusterByLeadSource or analyzer.ClusterByProductCode; empty
+// does not cluster). Journeys are arrows, A/B siblings dashed links, both with weights.
+func TopologyGraph(topology *analyzer.ConfigTopology, clusterBy string) *Graph {
+	graph := &Graph{
+		Title: fmt.Sprintf("Config Routing Topology - %s", topology.Folder),
+		Legend: []LegendEntry{
+			{StyleSuccess, "Normal flow type"},
+			{StyleWarning, "Automated flow type"},
+			{StyleInfo, "Semi-automated flow type"},
+			{StylePrimary, "CIF flow type"},
+			{StyleDanger, "Rejection flow type"},
+		},
+	}
+
+	clusters := make(map[string]int)
+	for _, node := range topology.Nodes {
+		box := Node{
+			ID: configNodeID(node.ConfigID),
+			Label: []string{
+				fmt.Sprintf("Config %d", node.ConfigID),
+				node.Name,
+				fmt.Sprintf("ui_version: %s", node.UIVersion),
+				fmt.Sprintf("flow_type: %s", node.FlowType),
+			},
+			Style: flowTypeStyle(node.FlowType),
+		}
+
+		value := node.ClusterValue(clusterBy)
+		if value == "" {
+			graph.Nodes = append(graph.Nodes, box)
+			continue
+		}
+		i, ok := clusters[value]
+		if !ok {
+			i = len(graph.Groups)
+			clusters[value] = i
+			graph.Groups = append(graph.Groups, Group{Label: fmt.Sprintf("%s: %s", clusterBy, value)})
+		}
+		graph.Groups[i].Nodes = append(graph.Groups[i].Nodes, box)
+	}
+
+	// Order clusters by value so the output is stable across config folders
+	sort.SliceStable(graph.Groups, func(a, b int) bool { return graph.Groups[a].Label < graph.Groups[b].Label })
+
+	for _, edge := range topology.Edges {
+		switch edge.Kind {
+		case analyzer.TopologyEdgeABSibling:
+			graph.Edges = append(graph.Edges, Edge{
+				From:       configNodeID(edge.From),
+				To:         configNodeID(edge.To),
+				Label:      fmt.Sprintf("A/B %d:%d", edge.FromWeight, edge.ToWeight),
+				Undirected: true,
+			})
+		default:
+			graph.Edges = append(graph.Edges, Edge{
+				From:  configNodeID(edge.From),
+				To:    configNodeID(edge.To),
+				Label: fmt.Sprintf("%s (weight %d)", edge.FlowType, edge.ToWeight),
+			})
+		}
+	}
+
+	return graph
+}
+
+// DOTRenderer renders graphs as Graphviz DOT, e.g. for `dot -Tsvg`. Groups become clusters.
+type DOTRenderer struct{}
+
+// RenderGraph renders a graph as a left-to-right digraph
+func (DOTRenderer) RenderGraph(g *Graph) string {
+	var dot strings.Builder
+
+	dot.WriteString(fmt.Sprintf("digraph %s {\n", dotQuote(g.Title)))
+	dot.WriteString(fmt.Sprintf("  graph [label=%s, labelloc=t, rankdir=LR, fontname=\"Helvetica\"];\n", dotQuote(g.Title)))
+	dot.WriteString(fmt.Sprintf("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\", fontcolor=\"#FFFFFF\", color=%q, fillcolor=%q];\n",
+		styleColors[StylePrimary], styleColors[StylePrimary]))
+	dot.WriteString("  edge [fontname=\"Helvetica\", fontsize=10, color=\"#222222\"];\n\n")
+
+	for i, group := range g.Groups {
+		dot.WriteString(fmt.Sprintf("  subgraph cluster_%d {\n", i))
+		dot.WriteString(fmt.Sprintf("    label=%s;\n", dotQuote(group.Label)))
+		dot.WriteString("    style=\"rounded,dashed\";\n")
+		dot.WriteString("    color=\"#9E9E9E\";\n")
+		for _, node := range group.Nodes {
+			dot.WriteString("    " + dotNode(node.ID, node.Label, node.Style))
+		}
+		dot.WriteString("  }\n\n")
+	}
+
+	for _, node := range g.Nodes {
+		dot.WriteString("  " + dotNode(node.ID, node.Label, node.Style))
+	}
+	if len(g.Nodes) > 0 {
+		dot.WriteString("\n")
+	}
+
+	for _, edge := range g.Edges {
+		var attrs []string
+		if edge.Label != "" {
+			attrs = append(attrs, "label="+dotQuote(edge.Label))
+		}
+		if edge.Undirected {
+			attrs = append(attrs, "dir=none", "style=dashed", fmt.Sprintf("color=%q", styleColors[StyleInfo]))
+		}
+		line := fmt.Sprintf("  %s -> %s", dotQuote(edge.From), dotQuote(edge.To))
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, ", ") + "]"
+		}
+		dot.WriteString(line + ";\n")
+	}
+
+	if len(g.Legend) > 0 {
+		dot.WriteString("\n  subgraph cluster_legend {\n")
+		dot.WriteString("    label=\"Legend\";\n")
+		dot.WriteString("    color=\"#9E9E9E\";\n")
+		for i, entry := range g.Legend {
+			dot.WriteString("    " + dotNode(fmt.Sprintf("legend_%d", i), []string{entry.Label}, entry.Style))
+		}
+		dot.WriteString("  }\n")
+	}
+
+	dot.WriteString("}\n")
+	return dot.String()
+}
+
+// WriteDOT renders a graph as DOT and writes it to filename
+func WriteDOT(g *Graph, filename string) error {
+	if err := ensureDir(filename); err != nil {
+		return fmt.Errorf("failed to prepare file path: %w", err)
+	}
+	if err := os.WriteFile(filename, []byte(DOTRenderer{}.RenderGraph(g)), 0644); err != nil {
+		return fmt.Errorf("failed to write DOT file %s: %w", filename, err)
+	}
+	return nil
+}
+
+func dotNode(id string, label []string, style Style) string {
+	attrs := []string{"label=" + dotQuote(strings.Join(label, "\n"))}
+	if color, ok := styleColors[style]; ok {
+		attrs = append(attrs, fmt.Sprintf("color=%q", color), fmt.Sprintf("fillcolor=%q", color))
+		if style == StyleWarning {
+			attrs = append(attrs, "fontcolor=\"#222222\"")
+		}
+	}
+	return fmt.Sprintf("%s [%s];\n", dotQuote(id), strings.Join(attrs, ", "))
+}
+
+// dotQuote quotes a DOT ID; newlines become centered line breaks
+func dotQuote(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(text) + `"`
+}
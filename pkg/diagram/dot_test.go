@@ -0,0 +1,51 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+)
+
+func TestTopologyDOT(t *testing.T) {
+	topology := &analyzer.ConfigTopology{
+		Folder: "evo",
+		Nodes: []analyzer.TopologyNode{
+			{ConfigID: 1, Name: "evo.auto", UIVersion: "v9.1.5.0", FlowType: "automated", ProductCode: "evo", LeadSources: []string{"organic"}},
+			{ConfigID: 2, Name: `evo "b"`, UIVersion: "v9.1.5.0", FlowType: "automated", ProductCode: "evo", LeadSources: []string{"organic"}},
+			{ConfigID: 3, Name: "evo.paid", UIVersion: "v9.1.4.0", FlowType: "semi", ProductCode: "evo", LeadSources: []string{"paid"}},
+			{ConfigID: 4, Name: "orphan", FlowType: "unknown"},
+		},
+		Edges: []analyzer.TopologyEdge{
+			{From: 1, To: 3, Kind: analyzer.TopologyEdgeJourney, FlowType: "automated_to_semi", ToWeight: 100},
+			{From: 1, To: 2, Kind: analyzer.TopologyEdgeABSibling, FromWeight: 70, ToWeight: 30},
+		},
+	}
+
+	dot := DOTRenderer{}.RenderGraph(TopologyGraph(topology, analyzer.ClusterByLeadSource))
+
+	for _, line := range []string{
+		`digraph "Config Routing Topology - evo" {`,
+		`    label="lead_source: organic";`,
+		`    "config_2" [label="Config 2\nevo \"b\"\nui_version: v9.1.5.0\nflow_type: automated", color="#ff9800", fillcolor="#ff9800", fontcolor="#222222"];`,
+		`  "config_4" [label="Config 4\norphan\nui_version: \nflow_type: unknown", color="#4CAF50", fillcolor="#4CAF50"];`,
+		`  "config_1" -> "config_3" [label="automated_to_semi (weight 100)"];`,
+		`  "config_1" -> "config_2" [label="A/B 70:30", dir=none, style=dashed, color="#9C27B0"];`,
+	} {
+		if !strings.Contains(dot, line+"\n") {
+			t.Errorf("DOT output is missing line %s:\n%s", line, dot)
+		}
+	}
+
+	// Clusters are ordered by value and unclustered configs stay outside
+	organic := strings.Index(dot, `label="lead_source: organic"`)
+	paid := strings.Index(dot, `label="lead_source: paid"`)
+	if organic < 0 || paid < organic || strings.Count(dot, "subgraph cluster_") != 3 {
+		t.Errorf("expected organic, paid and legend clusters:\n%s", dot)
+	}
+
+	byProduct := TopologyGraph(topology, analyzer.ClusterByProductCode)
+	if len(byProduct.Groups) != 1 || len(byProduct.Groups[0].Nodes) != 3 || len(byProduct.Nodes) != 1 {
+		t.Errorf("expected one product_code cluster, got %+v", byProduct.Groups)
+	}
+}
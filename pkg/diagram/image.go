@@ -0,0 +1,239 @@
+package diagram
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+)
+
+// ImageBackend chooses how diagrams are rendered to images
+type ImageBackend string
+
+// Supported image backends
+const (
+	// ImageBackendAuto uses PlantUML when Java and plantuml.jar are available and the
+	// built-in renderer otherwise
+	ImageBackendAuto ImageBackend = "auto"
+	// ImageBackendPlantUML always renders PNGs with `java -jar plantuml.jar`
+	ImageBackendPlantUML ImageBackend = "plantuml"
+	// ImageBackendBuiltin always renders PNGs in Go
+	ImageBackendBuiltin ImageBackend = "builtin"
+)
+
+// ParseImageBackend parses an image backend name; empty means ImageBackendAuto
+func ParseImageBackend(name string) (ImageBackend, error) {
+	switch backend := ImageBackend(strings.ToLower(strings.TrimSpace(name))); backend {
+	case "":
+		return ImageBackendAuto, nil
+	case ImageBackendAuto, ImageBackendPlantUML, ImageBackendBuiltin:
+		return backend, nil
+	default:
+		return "", fmt.Errorf("unknown image renderer %q (want auto, plantuml or builtin)", name)
+	}
+}
+
+// PlantUMLAvailable reports whether ExportPlantUMLToPNG can run, i.e. java is in PATH and
+// plantuml.jar is in the working directory
+func PlantUMLAvailable() bool {
+	if _, err := exec.LookPath("java"); err != nil {
+		return false
+	}
+	_, err := os.Stat("plantuml.jar")
+	return err == nil
+}
+
+// Metrics of the built-in renderer, in image units (SVG pixels)
+const (
+	imageCharWidth  = 7.0
+	imageLineHeight = 16.0
+	imageFontSize   = 12
+	imagePadding    = 8.0
+	imageMargin     = 20.0
+	imageGap        = 32.0
+)
+
+// Colors of the built-in renderer besides the style colors
+const (
+	inkColor   = "#222222"
+	paperColor = "#FFFFFF"
+	frameColor = "#9E9E9E"
+	boxColor   = "#F5F5F5"
+	noteColor  = "#FFF9C4"
+)
+
+// Image is a diagram laid out by the built-in renderer. It is written as SVG or rasterised
+// to PNG without Java, see GraphImage and ActivityImage.
+type Image struct {
+	Title  string
+	Width  float64
+	Height float64
+	shapes []shape
+}
+
+type shapeKind int
+
+const (
+	shapeRect shapeKind = iota
+	shapeDiamond
+	shapeCircle
+	shapePolygon
+	shapeLine
+	shapeText
+)
+
+type point struct{ X, Y float64 }
+
+type rect struct{ X, Y, W, H float64 }
+
+func (r rect) center() point {
+	return point{r.X + r.W/2, r.Y + r.H/2}
+}
+
+func (r rect) bottom() float64 {
+	return r.Y + r.H
+}
+
+// shape is a drawing primitive. Rects, diamonds and circles fill and stroke their bounds,
+// polygons and lines use points, text is centered (or left aligned) in its bounds.
+type shape struct {
+	kind      shapeKind
+	bounds    rect
+	points    []point
+	fill      string
+	stroke    string
+	dashed    bool
+	lines     []string
+	leftAlign bool
+	bold      bool
+}
+
+// textSize measures lines of text in the monospace font of the renderer
+func textSize(lines []string) (float64, float64) {
+	var width int
+	for _, line := range lines {
+		width = max(width, utf8.RuneCountInString(line))
+	}
+	return float64(width) * imageCharWidth, float64(len(lines)) * imageLineHeight
+}
+
+// boxSize is the size of a box holding lines of text
+func boxSize(lines []string) (float64, float64) {
+	w, h := textSize(lines)
+	return w + 3*imagePadding, h + 2*imagePadding
+}
+
+// styleInk returns the fill, stroke and text colors of a style
+func styleInk(style Style) (fill, stroke, text string) {
+	color, ok := styleColors[style]
+	if !ok {
+		return boxColor, frameColor, inkColor
+	}
+	if style == StyleWarning {
+		return color, color, inkColor
+	}
+	return color, color, paperColor
+}
+
+func (img *Image) add(s shape) {
+	img.shapes = append(img.shapes, s)
+}
+
+// box draws a styled box with centered lines
+func (img *Image) box(bounds rect, lines []string, style Style) {
+	fill, stroke, text := styleInk(style)
+	img.add(shape{kind: shapeRect, bounds: bounds, fill: fill, stroke: stroke})
+	img.text(bounds, lines, text)
+}
+
+func (img *Image) text(bounds rect, lines []string, color string) {
+	img.add(shape{kind: shapeText, bounds: bounds, lines: lines, fill: color})
+}
+
+// label draws a line of text on a paper background centered on p, e.g. on an edge
+func (img *Image) label(p point, text string) {
+	w, h := textSize([]string{text})
+	bounds := rect{p.X - w/2 - 2, p.Y - h/2, w + 4, h}
+	img.add(shape{kind: shapeRect, bounds: bounds, fill: paperColor})
+	img.text(bounds, []string{text}, inkColor)
+}
+
+// note draws a line of left aligned text with its top left at p, e.g. next to an edge
+func (img *Image) note(p point, text string) {
+	w, h := textSize([]string{text})
+	img.add(shape{kind: shapeText, bounds: rect{p.X, p.Y, w, h}, lines: []string{text}, fill: inkColor, leftAlign: true})
+}
+
+// arrow draws a polyline with an arrow head at its last point
+func (img *Image) arrow(points []point, color string, dashed bool) {
+	img.add(shape{kind: shapeLine, points: points, stroke: color, dashed: dashed})
+
+	from, to := points[len(points)-2], points[len(points)-1]
+	dx, dy := to.X-from.X, to.Y-from.Y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	ux, uy := dx/length, dy/length
+	const headLength, headWidth = 10.0, 4.5
+	base := point{to.X - ux*headLength, to.Y - uy*headLength}
+	img.add(shape{kind: shapePolygon, fill: color, points: []point{
+		to,
+		{base.X - uy*headWidth, base.Y + ux*headWidth},
+		{base.X + uy*headWidth, base.Y - ux*headWidth},
+	}})
+}
+
+// stop draws the end of an activity: a ring around a filled circle
+func (img *Image) stop(center point) {
+	const radius = 10.0
+	img.add(shape{kind: shapeCircle, bounds: rect{center.X - radius, center.Y - radius, 2 * radius, 2 * radius}, fill: paperColor, stroke: inkColor})
+	img.add(shape{kind: shapeCircle, bounds: rect{center.X - radius + 4, center.Y - radius + 4, 2*radius - 8, 2*radius - 8}, fill: inkColor})
+}
+
+// finish moves the laid out shapes below the title, inside the margins, and sizes the image
+func (img *Image) finish() {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	extend := func(p point) {
+		minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+		maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+	}
+	for _, s := range img.shapes {
+		if s.kind == shapeLine || s.kind == shapePolygon {
+			for _, p := range s.points {
+				extend(p)
+			}
+			continue
+		}
+		extend(point{s.bounds.X, s.bounds.Y})
+		extend(point{s.bounds.X + s.bounds.W, s.bounds.bottom()})
+	}
+	if len(img.shapes) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+
+	top := imageMargin
+	titleWidth, titleHeight := textSize([]string{img.Title})
+	if img.Title != "" {
+		top += titleHeight + imagePadding*2
+	}
+	dx, dy := imageMargin-minX, top-minY
+	for i := range img.shapes {
+		s := &img.shapes[i]
+		s.bounds.X += dx
+		s.bounds.Y += dy
+		for j := range s.points {
+			s.points[j].X += dx
+			s.points[j].Y += dy
+		}
+	}
+
+	img.Width = math.Ceil(math.Max(maxX-minX, titleWidth) + 2*imageMargin)
+	img.Height = math.Ceil(maxY - minY + top + imageMargin)
+	if img.Title != "" {
+		img.add(shape{kind: shapeText, bounds: rect{0, imageMargin, img.Width, titleHeight}, lines: []string{img.Title}, fill: inkColor, bold: true})
+	}
+}
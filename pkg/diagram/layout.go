@@ -0,0 +1,326 @@
+package diagram
+
+import (
+	"math"
+)
+
+// GraphImage lays out a graph from left to right. Nodes outside groups are placed in
+// columns by their distance from the nodes without incoming edges; each group is a framed
+// column after them. The legend goes below the graph.
+func GraphImage(g *Graph) *Image {
+	img := &Image{Title: g.Title}
+
+	// Columns leave room for the longest edge label between them
+	gap := imageGap * 2
+	for _, edge := range g.Edges {
+		w, _ := textSize([]string{edge.Label})
+		gap = math.Max(gap, w+imageGap)
+	}
+
+	type column struct {
+		label string
+		nodes []Node
+	}
+	var columns []column
+	for _, nodes := range graphRanks(g) {
+		columns = append(columns, column{nodes: nodes})
+	}
+	for _, group := range g.Groups {
+		columns = append(columns, column{label: group.Label, nodes: group.Nodes})
+	}
+
+	// Measure the columns, framed ones include the frame and its label
+	widths := make([]float64, len(columns))
+	heights := make([]float64, len(columns))
+	var tallest float64
+	for i, col := range columns {
+		for j, node := range col.nodes {
+			w, h := boxSize(node.Label)
+			widths[i] = math.Max(widths[i], w)
+			heights[i] += h
+			if j > 0 {
+				heights[i] += imagePadding * 2
+			}
+		}
+		if col.label != "" {
+			w, _ := textSize([]string{col.label})
+			widths[i] = math.Max(widths[i], w) + imagePadding*2
+			heights[i] += imageLineHeight + imagePadding*3
+		}
+		tallest = math.Max(tallest, heights[i])
+	}
+
+	// Place the columns, vertically centered
+	boxes := make(map[string]rect)
+	var x float64
+	for i, col := range columns {
+		y := (tallest - heights[i]) / 2
+		inner := widths[i]
+		if col.label != "" {
+			frame := rect{x, y, widths[i], heights[i]}
+			img.add(shape{kind: shapeRect, bounds: frame, stroke: frameColor, dashed: true})
+			img.add(shape{kind: shapeText, bounds: rect{x + imagePadding, y + imagePadding, widths[i] - imagePadding*2, imageLineHeight}, lines: []string{col.label}, fill: inkColor, leftAlign: true, bold: true})
+			y += imageLineHeight + imagePadding*2
+			inner -= imagePadding * 2
+		}
+		for _, node := range col.nodes {
+			w, h := boxSize(node.Label)
+			bounds := rect{x + (widths[i]-w)/2, y, w, h}
+			if col.label != "" {
+				bounds = rect{x + imagePadding, y, inner, h}
+			}
+			boxes[node.ID] = bounds
+			img.box(bounds, node.Label, node.Style)
+			y += h + imagePadding*2
+		}
+		x += widths[i] + gap
+	}
+
+	// Edges are straight between the box borders; edges with a reverse edge are shifted
+	// aside so both stay visible
+	directed := make(map[[2]string]bool)
+	for _, edge := range g.Edges {
+		directed[[2]string{edge.From, edge.To}] = true
+	}
+	for _, edge := range g.Edges {
+		from, okFrom := boxes[edge.From]
+		to, okTo := boxes[edge.To]
+		if !okFrom || !okTo || edge.From == edge.To {
+			continue
+		}
+		start := clipToBox(from, to.center())
+		end := clipToBox(to, from.center())
+		if directed[[2]string{edge.To, edge.From}] {
+			dx, dy := end.X-start.X, end.Y-start.Y
+			length := math.Hypot(dx, dy)
+			start = point{start.X - dy/length*6, start.Y + dx/length*6}
+			end = point{end.X - dy/length*6, end.Y + dx/length*6}
+		}
+
+		if edge.Undirected {
+			img.add(shape{kind: shapeLine, points: []point{start, end}, stroke: styleColors[StyleInfo], dashed: true})
+		} else {
+			img.arrow([]point{start, end}, inkColor, false)
+		}
+		if edge.Label != "" {
+			img.label(point{(start.X + end.X) / 2, (start.Y + end.Y) / 2}, edge.Label)
+		}
+	}
+
+	if len(g.Legend) > 0 {
+		img.legend(point{0, tallest + imageGap}, g.Legend)
+	}
+
+	img.finish()
+	return img
+}
+
+// graphRanks puts the nodes outside groups in columns. Nodes without incoming edges, or the
+// first unplaced node of a cycle, start a column; every other node goes one column after
+// the first node reaching it.
+func graphRanks(g *Graph) [][]Node {
+	index := make(map[string]int, len(g.Nodes))
+	for i, node := range g.Nodes {
+		index[node.ID] = i
+	}
+	next := make(map[string][]string)
+	incoming := make(map[string]bool)
+	for _, edge := range g.Edges {
+		_, okFrom := index[edge.From]
+		_, okTo := index[edge.To]
+		if edge.Undirected || !okFrom || !okTo || edge.From == edge.To {
+			continue
+		}
+		next[edge.From] = append(next[edge.From], edge.To)
+		incoming[edge.To] = true
+	}
+
+	rank := make(map[string]int, len(g.Nodes))
+	visit := func(root string) {
+		rank[root] = 0
+		queue := []string{root}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, to := range next[id] {
+				if _, ok := rank[to]; !ok {
+					rank[to] = rank[id] + 1
+					queue = append(queue, to)
+				}
+			}
+		}
+	}
+	for _, node := range g.Nodes {
+		if _, ok := rank[node.ID]; !ok && !incoming[node.ID] {
+			visit(node.ID)
+		}
+	}
+	for _, node := range g.Nodes {
+		if _, ok := rank[node.ID]; !ok {
+			visit(node.ID)
+		}
+	}
+
+	var ranks [][]Node
+	for _, node := range g.Nodes {
+		for len(ranks) <= rank[node.ID] {
+			ranks = append(ranks, nil)
+		}
+		ranks[rank[node.ID]] = append(ranks[rank[node.ID]], node)
+	}
+	return ranks
+}
+
+// clipToBox returns where the line from the center of a box towards p leaves the box
+func clipToBox(box rect, p point) point {
+	c := box.center()
+	dx, dy := p.X-c.X, p.Y-c.Y
+	if dx == 0 && dy == 0 {
+		return c
+	}
+	scale := math.Inf(1)
+	if dx != 0 {
+		scale = math.Min(scale, box.W/2/math.Abs(dx))
+	}
+	if dy != 0 {
+		scale = math.Min(scale, box.H/2/math.Abs(dy))
+	}
+	return point{c.X + dx*scale, c.Y + dy*scale}
+}
+
+// legend draws a framed list of style swatches with their meaning at top left p
+func (img *Image) legend(p point, entries []LegendEntry) {
+	const swatch = 12.0
+	var width float64
+	for _, entry := range entries {
+		w, _ := textSize([]string{entry.Label})
+		width = math.Max(width, w)
+	}
+	width += swatch + imagePadding*3
+	height := float64(len(entries)+1)*imageLineHeight + imagePadding*2
+
+	img.add(shape{kind: shapeRect, bounds: rect{p.X, p.Y, width, height}, fill: paperColor, stroke: frameColor})
+	img.add(shape{kind: shapeText, bounds: rect{p.X + imagePadding, p.Y + imagePadding, width - imagePadding*2, imageLineHeight}, lines: []string{"Legend"}, fill: inkColor, leftAlign: true, bold: true})
+	for i, entry := range entries {
+		y := p.Y + imagePadding + float64(i+1)*imageLineHeight
+		fill, stroke, _ := styleInk(entry.Style)
+		img.add(shape{kind: shapeRect, bounds: rect{p.X + imagePadding, y + (imageLineHeight-swatch)/2, swatch, swatch}, fill: fill, stroke: stroke})
+		w, _ := textSize([]string{entry.Label})
+		img.add(shape{kind: shapeText, bounds: rect{p.X + imagePadding*2 + swatch, y, w, imageLineHeight}, lines: []string{entry.Label}, fill: inkColor, leftAlign: true})
+	}
+}
+
+// ActivityImage lays out an activity from top to bottom. Decision steps are diamonds with
+// a rejected branch to a stop on their right; the choices of a step sit side by side below
+// it, each leading to the next step. The note goes to the right of the activity.
+func ActivityImage(a *Activity) *Image {
+	img := &Image{Title: a.Title}
+
+	// tails are the points, and their edge labels, leading into the next element
+	type tail struct {
+		at    point
+		label string
+	}
+	connect := func(tails []tail, to point) {
+		for _, t := range tails {
+			points := []point{t.at, to}
+			if math.Abs(t.at.X-to.X) > 0.5 {
+				// Route around with a horizontal segment halfway down the gap
+				bend := to.Y - imageGap/2
+				points = []point{t.at, {t.at.X, bend}, {to.X, bend}, to}
+			}
+			img.arrow(points, inkColor, false)
+			if t.label != "" {
+				img.note(point{t.at.X + 4, t.at.Y}, t.label)
+			}
+		}
+	}
+
+	const radius = 10.0
+	img.add(shape{kind: shapeCircle, bounds: rect{-radius, 0, 2 * radius, 2 * radius}, fill: inkColor})
+	tails := []tail{{at: point{0, 2 * radius}}}
+	y := 2*radius + imageGap
+
+	for _, step := range a.Steps {
+		w, h := boxSize(step.Label)
+		if step.Decision {
+			w, h = w*1.6, h*1.8
+		}
+		bounds := rect{-w / 2, y, w, h}
+		connect(tails, point{0, y})
+		if step.Decision {
+			fill, stroke, text := styleInk(StyleWarning)
+			img.add(shape{kind: shapeDiamond, bounds: bounds, fill: fill, stroke: stroke})
+			img.text(bounds, step.Label, text)
+
+			// The rejected branch ends the activity
+			right := point{bounds.X + bounds.W, bounds.center().Y}
+			labelWidth, _ := textSize([]string{"rejected"})
+			stop := point{right.X + labelWidth + imageGap, right.Y}
+			img.arrow([]point{right, {stop.X - radius, stop.Y}}, inkColor, false)
+			img.note(point{right.X + 4, right.Y - imageLineHeight}, "rejected")
+			img.stop(stop)
+			tails = []tail{{at: point{0, bounds.bottom()}, label: "approved"}}
+		} else {
+			img.box(bounds, step.Label, StylePrimary)
+			tails = []tail{{at: point{0, bounds.bottom()}}}
+		}
+		y = bounds.bottom() + imageGap
+
+		if len(step.Choices) == 0 {
+			continue
+		}
+		type branch struct {
+			label []string
+			style Style
+		}
+		var branches []branch
+		for _, choice := range step.Choices {
+			branches = append(branches, branch{append([]string{"if " + choice.Condition}, choice.Label...), StyleSuccess})
+		}
+		branches = append(branches, branch{append([]string{"else"}, step.Fallback...), StylePrimary})
+
+		var total, tallest float64
+		for i, b := range branches {
+			w, h := boxSize(b.label)
+			total += w
+			if i > 0 {
+				total += imagePadding * 2
+			}
+			tallest = math.Max(tallest, h)
+		}
+		var exits []tail
+		x := -total / 2
+		for _, b := range branches {
+			w, _ := boxSize(b.label)
+			bounds := rect{x, y, w, tallest}
+			connect(tails, point{bounds.center().X, y})
+			img.box(bounds, b.label, b.style)
+			exits = append(exits, tail{at: point{bounds.center().X, bounds.bottom()}})
+			x += w + imagePadding*2
+		}
+		tails = exits
+		y += tallest + imageGap
+	}
+
+	connect(tails, point{0, y})
+	img.stop(point{0, y + radius})
+
+	if len(a.Note) > 0 {
+		// The note starts at the top, right of everything drawn so far
+		right := math.Inf(-1)
+		for _, s := range img.shapes {
+			right = math.Max(right, s.bounds.X+s.bounds.W)
+			for _, p := range s.points {
+				right = math.Max(right, p.X)
+			}
+		}
+		w, h := boxSize(a.Note)
+		bounds := rect{right + imageGap, 0, w, h}
+		img.add(shape{kind: shapeRect, bounds: bounds, fill: noteColor, stroke: frameColor})
+		img.add(shape{kind: shapeText, bounds: rect{bounds.X + imagePadding, bounds.Y + imagePadding, w - imagePadding*2, h - imagePadding*2}, lines: a.Note, fill: inkColor, leftAlign: true})
+	}
+
+	img.finish()
+	return img
+}
@@ -0,0 +1,185 @@
+package diagram
+
+import (
+	"testing"
+)
+
+// shapesOf returns the shapes of a kind in drawing order
+func shapesOf(img *Image, kind shapeKind) []shape {
+	var result []shape
+	for _, s := range img.shapes {
+		if s.kind == kind {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// inside reports whether every shape of the image lies within its size
+func inside(img *Image) bool {
+	for _, s := range img.shapes {
+		points := append([]point{{s.bounds.X, s.bounds.Y}, {s.bounds.X + s.bounds.W, s.bounds.bottom()}}, s.points...)
+		for _, p := range points {
+			if p.X < 0 || p.Y < 0 || p.X > img.Width || p.Y > img.Height {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestGraphImage(t *testing.T) {
+	img := GraphImage(JourneyFlowGraph(testTemplate()))
+
+	if img.Title != "Journey Flow Analysis - Config 9054" || !inside(img) {
+		t.Fatalf("expected a titled image containing all shapes, got %vx%v", img.Width, img.Height)
+	}
+
+	// The source is in the first column, both targets in the second, one above the other
+	var boxes []rect
+	for _, s := range shapesOf(img, shapeRect) {
+		if s.stroke != "" && s.stroke != frameColor {
+			boxes = append(boxes, s.bounds)
+		}
+	}
+	// Config boxes are drawn before the legend swatches
+	if len(boxes) != 3+6 {
+		t.Fatalf("expected 3 config boxes and 6 swatches, got %d", len(boxes))
+	}
+	source, rejected, automated := boxes[0], boxes[1], boxes[2]
+	if rejected.X <= source.X+source.W || rejected.X != automated.X || automated.Y <= rejected.bottom() {
+		t.Errorf("unexpected columns: source %+v, targets %+v %+v", source, rejected, automated)
+	}
+
+	// One arrow, starting on the source border, per journey to another config
+	lines := shapesOf(img, shapeLine)
+	if len(lines) != 2 || len(shapesOf(img, shapePolygon)) != 2 {
+		t.Fatalf("expected 2 arrows, got %d lines", len(lines))
+	}
+	if start := lines[0].points[0]; start.X != source.X+source.W {
+		t.Errorf("expected the arrow to start on the right border of the source, got %+v", start)
+	}
+}
+
+func TestGraphImageGroups(t *testing.T) {
+	graph := &Graph{
+		Groups: []Group{
+			{Label: "Group 1: evo.auto", Nodes: []Node{{ID: "a", Label: []string{"Config 1"}}, {ID: "b", Label: []string{"Config 2"}}}},
+			{Label: "Group 2: evo.semi", Nodes: []Node{{ID: "c", Label: []string{"Config 3"}}}},
+		},
+		Edges: []Edge{
+			{From: "a", To: "b", Label: "A/B 70:30", Undirected: true},
+			{From: "a", To: "c"},
+			{From: "c", To: "a"},
+		},
+	}
+	img := GraphImage(graph)
+
+	var frames []rect
+	for _, s := range shapesOf(img, shapeRect) {
+		if s.dashed {
+			frames = append(frames, s.bounds)
+		}
+	}
+	if len(frames) != 2 || frames[1].X <= frames[0].X+frames[0].W {
+		t.Fatalf("expected two frames side by side, got %+v", frames)
+	}
+
+	lines := shapesOf(img, shapeLine)
+	if len(lines) != 3 || !lines[0].dashed || len(shapesOf(img, shapePolygon)) != 2 {
+		t.Fatalf("expected a dashed link and two arrows, got %+v", lines)
+	}
+	// Opposite edges are drawn apart
+	if lines[1].points[0] == lines[2].points[1] {
+		t.Errorf("expected opposite edges to be shifted apart, got %+v and %+v", lines[1].points, lines[2].points)
+	}
+}
+
+func TestGraphRanks(t *testing.T) {
+	graph := &Graph{
+		Nodes: []Node{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}, {ID: "e"}},
+		Edges: []Edge{
+			{From: "a", To: "b"},
+			{From: "b", To: "c"},
+			{From: "c", To: "b"},
+			{From: "a", To: "d", Undirected: true},
+			{From: "e", To: "e"},
+		},
+	}
+
+	ranks := graphRanks(graph)
+	want := [][]string{{"a", "d", "e"}, {"b"}, {"c"}}
+	if len(ranks) != len(want) {
+		t.Fatalf("expected %d ranks, got %+v", len(want), ranks)
+	}
+	for i := range want {
+		if len(ranks[i]) != len(want[i]) {
+			t.Fatalf("rank %d: got %+v, want %v", i, ranks[i], want[i])
+		}
+		for j, id := range want[i] {
+			if ranks[i][j].ID != id {
+				t.Errorf("rank %d: got %+v, want %v", i, ranks[i], want[i])
+			}
+		}
+	}
+
+	// Without roots, the first node starts the cycle
+	cycle := graphRanks(&Graph{Nodes: []Node{{ID: "x"}, {ID: "y"}}, Edges: []Edge{{From: "x", To: "y"}, {From: "y", To: "x"}}})
+	if len(cycle) != 2 || cycle[0][0].ID != "x" {
+		t.Errorf("expected x before y, got %+v", cycle)
+	}
+}
+
+func TestActivityImage(t *testing.T) {
+	img := ActivityImage(JourneyStepsActivity(testJourney()))
+
+	if !inside(img) {
+		t.Fatalf("expected all shapes inside %vx%v", img.Width, img.Height)
+	}
+
+	// The decision step is a diamond, its rejected branch and the end are stops
+	diamonds := shapesOf(img, shapeDiamond)
+	if len(diamonds) != 1 {
+		t.Fatalf("expected one decision diamond, got %d", len(diamonds))
+	}
+	circles := shapesOf(img, shapeCircle)
+	if len(circles) != 5 {
+		t.Fatalf("expected a start and two stops of two circles each, got %d circles", len(circles))
+	}
+	rejected := circles[1].bounds.center()
+	end := circles[3].bounds.center()
+	if rejected.X <= diamonds[0].bounds.X+diamonds[0].bounds.W || rejected.Y != diamonds[0].bounds.center().Y {
+		t.Errorf("expected the rejected stop right of the diamond, got %+v", rejected)
+	}
+
+	// Both conditions and the fallback sit side by side below step 1
+	var branches []rect
+	for _, s := range shapesOf(img, shapeText) {
+		if len(s.lines) > 0 && (s.lines[0] == "if telco_code equals viettel" || s.lines[0] == "if telco_code equals mobifone" || s.lines[0] == "else") {
+			branches = append(branches, s.bounds)
+		}
+	}
+	if len(branches) != 3 || branches[0].Y != branches[2].Y || branches[1].X <= branches[0].X+branches[0].W {
+		t.Errorf("expected three branches in a row, got %+v", branches)
+	}
+	if diamonds[0].bounds.Y <= branches[0].bottom() || end.Y <= diamonds[0].bounds.bottom() {
+		t.Errorf("expected step 2 below the branches of step 1 and the end below step 2")
+	}
+}
+
+func TestParseImageBackend(t *testing.T) {
+	for name, want := range map[string]ImageBackend{
+		"":          ImageBackendAuto,
+		"auto":      ImageBackendAuto,
+		"PlantUML":  ImageBackendPlantUML,
+		" builtin ": ImageBackendBuiltin,
+	} {
+		got, err := ParseImageBackend(name)
+		if err != nil || got != want {
+			t.Errorf("%q: got %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseImageBackend("graphviz"); err == nil {
+		t.Error("expected an error for graphviz")
+	}
+}
@@ -0,0 +1,187 @@
+package diagram
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MermaidRenderer renders diagrams as Mermaid flowcharts, which GitHub and most wikis
+// display natively
+type MermaidRenderer struct{}
+
+// Format returns FormatMermaid
+func (MermaidRenderer) Format() Format {
+	return FormatMermaid
+}
+
+// RenderGraph renders a graph as a left-to-right flowchart with a subgraph per group
+func (MermaidRenderer) RenderGraph(g *Graph) string {
+	var mmd strings.Builder
+
+	writeMermaidHeader(&mmd, g.Title, "LR")
+
+	for i, group := range g.Groups {
+		mmd.WriteString(fmt.Sprintf("  subgraph group_%d[\"%s\"]\n", i, mermaidEscape(group.Label)))
+		for _, node := range group.Nodes {
+			mmd.WriteString("    " + mermaidNode(node.ID, "[", "]", node.Label, node.Style))
+		}
+		mmd.WriteString("  end\n")
+	}
+
+	for _, node := range g.Nodes {
+		mmd.WriteString("  " + mermaidNode(node.ID, "[", "]", node.Label, node.Style))
+	}
+
+	for _, edge := range g.Edges {
+		if edge.Undirected {
+			mmd.WriteString("  " + mermaidLink(edge.From, edge.To, edge.Label))
+			continue
+		}
+		mmd.WriteString("  " + mermaidEdge(edge.From, edge.To, edge.Label))
+	}
+
+	if len(g.Legend) > 0 {
+		// Mermaid has no legend, so the legend is a subgraph of styled boxes
+		mmd.WriteString("  subgraph legend[\"Legend\"]\n")
+		mmd.WriteString("    direction TB\n")
+		for i, entry := range g.Legend {
+			mmd.WriteString("    " + mermaidNode(fmt.Sprintf("legend_%d", i), "[", "]", []string{entry.Label}, entry.Style))
+		}
+		mmd.WriteString("  end\n")
+	}
+
+	writeMermaidClasses(&mmd)
+	return mmd.String()
+}
+
+// RenderActivity renders an activity as a top-down flowchart. Decision steps are rhombi
+// with a rejected edge to a stop node; choices are chained rhombi whose UI version boxes
+// all lead to the next step.
+func (MermaidRenderer) RenderActivity(a *Activity) string {
+	var mmd strings.Builder
+
+	writeMermaidHeader(&mmd, a.Title, "TB")
+	mmd.WriteString("  begin((start)):::success\n")
+
+	// tails are the nodes, and edge labels, leading into the next step
+	type tail struct{ node, label string }
+	tails := []tail{{node: "begin"}}
+	connect := func(to string) {
+		for _, t := range tails {
+			mmd.WriteString("  " + mermaidEdge(t.node, to, t.label))
+		}
+	}
+
+	for i, step := range a.Steps {
+		id := fmt.Sprintf("step_%d", i)
+		if step.Decision {
+			mmd.WriteString("  " + mermaidNode(id, "{", "}", step.Label, StyleWarning))
+		} else {
+			mmd.WriteString("  " + mermaidNode(id, "[", "]", step.Label, StylePrimary))
+		}
+		connect(id)
+		tails = []tail{{node: id}}
+
+		if step.Decision {
+			mmd.WriteString(fmt.Sprintf("  %s_rejected((stop)):::danger\n", id))
+			mmd.WriteString("  " + mermaidEdge(id, id+"_rejected", "rejected"))
+			tails = []tail{{node: id, label: "approved"}}
+		}
+
+		if len(step.Choices) == 0 {
+			continue
+		}
+		var exits []tail
+		for k, choice := range step.Choices {
+			choiceID := fmt.Sprintf("%s_if_%d", id, k)
+			versionID := fmt.Sprintf("%s_then_%d", id, k)
+			mmd.WriteString("  " + mermaidNode(choiceID, "{", "}", []string{choice.Condition + "?"}, StyleWarning))
+			connect(choiceID)
+			mmd.WriteString("  " + mermaidNode(versionID, "[", "]", choice.Label, StyleSuccess))
+			mmd.WriteString("  " + mermaidEdge(choiceID, versionID, "yes"))
+			exits = append(exits, tail{node: versionID})
+			tails = []tail{{node: choiceID, label: "no"}}
+		}
+		fallbackID := id + "_else"
+		mmd.WriteString("  " + mermaidNode(fallbackID, "[", "]", step.Fallback, StylePrimary))
+		connect(fallbackID)
+		tails = append(exits, tail{node: fallbackID})
+	}
+
+	mmd.WriteString("  finish((stop)):::danger\n")
+	connect("finish")
+
+	if len(a.Note) > 0 {
+		var note []string
+		for _, line := range a.Note {
+			if line != "" {
+				note = append(note, line)
+			}
+		}
+		mmd.WriteString("  " + mermaidNode("note", "[", "]", note, StyleDefault))
+		mmd.WriteString("  finish -.- note\n")
+	}
+
+	writeMermaidClasses(&mmd)
+	return mmd.String()
+}
+
+// writeMermaidHeader writes the title front matter and the flowchart direction
+func writeMermaidHeader(mmd *strings.Builder, title, direction string) {
+	if title != "" {
+		mmd.WriteString("---\n")
+		mmd.WriteString(fmt.Sprintf("title: %q\n", title))
+		mmd.WriteString("---\n")
+	}
+	mmd.WriteString(fmt.Sprintf("flowchart %s\n", direction))
+}
+
+// writeMermaidClasses defines a class per style with the Materia theme colors
+func writeMermaidClasses(mmd *strings.Builder) {
+	for _, style := range []Style{StylePrimary, StyleSuccess, StyleWarning, StyleDanger, StyleInfo} {
+		fontColor := "#FFF"
+		if style == StyleWarning {
+			fontColor = "#222"
+		}
+		mmd.WriteString(fmt.Sprintf("  classDef %s fill:%s,stroke:%s,color:%s\n", style, styleColors[style], styleColors[style], fontColor))
+	}
+}
+
+// mermaidNode writes a node with the given shape brackets, e.g. "[" "]" for a box or "{" "}" for a rhombus
+func mermaidNode(id, open, close string, label []string, style Style) string {
+	lines := make([]string, len(label))
+	for i, line := range label {
+		lines[i] = mermaidEscape(line)
+	}
+
+	node := fmt.Sprintf("%s%s\"%s\"%s", id, open, strings.Join(lines, "<br/>"), close)
+	if style != StyleDefault {
+		node += ":::" + string(style)
+	}
+	return node + "\n"
+}
+
+func mermaidEdge(from, to, label string) string {
+	if label == "" {
+		return fmt.Sprintf("%s --> %s\n", from, to)
+	}
+	return fmt.Sprintf("%s -->|\"%s\"| %s\n", from, mermaidEscape(label), to)
+}
+
+// mermaidLink writes a dashed link without arrow head
+func mermaidLink(from, to, label string) string {
+	if label == "" {
+		return fmt.Sprintf("%s -.- %s\n", from, to)
+	}
+	return fmt.Sprintf("%s -.-|\"%s\"| %s\n", from, mermaidEscape(label), to)
+}
+
+// mermaidEscape replaces the characters that end a quoted Mermaid label with entity codes
+func mermaidEscape(text string) string {
+	return strings.NewReplacer(
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+		"|", "#124;",
+	).Replace(text)
+}
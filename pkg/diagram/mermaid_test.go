@@ -0,0 +1,57 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMermaidActivityBranches(t *testing.T) {
+	mmd := MermaidRenderer{}.RenderActivity(JourneyStepsActivity(testJourney()))
+
+	for _, line := range []string{
+		"flowchart TB",
+		"  begin --> step_0",
+		"  step_0 --> step_1",
+		`  step_1_if_0{"telco_code equals viettel?"}:::warning`,
+		`  step_1_if_0 -->|"yes"| step_1_then_0`,
+		`  step_1_if_0 -->|"no"| step_1_if_1`,
+		`  step_1_if_1 -->|"no"| step_1_else`,
+		// Every UI version branch joins the next step
+		"  step_1_then_0 --> step_2",
+		"  step_1_then_1 --> step_2",
+		"  step_1_else --> step_2",
+		`  step_2{"Step 2: appraising.cif<br/>UI Version: v2"}:::warning`,
+		`  step_2 -->|"rejected"| step_2_rejected`,
+		`  step_2 -->|"approved"| finish`,
+	} {
+		if !strings.Contains(mmd, line+"\n") {
+			t.Errorf("Mermaid output is missing line %q:\n%s", line, mmd)
+		}
+	}
+}
+
+func TestMermaidGraph(t *testing.T) {
+	graph := &Graph{
+		Title:  `Flow "A"`,
+		Groups: []Group{{Label: "Group 1: collect", Nodes: []Node{{ID: "config_0_0", Label: []string{"Config 1", "- step <x>"}}}}},
+		Edges: []Edge{
+			{From: "config_0_0", To: "config_0_1", Label: "a|b"},
+			{From: "config_0_0", To: "config_0_2", Label: "A/B 70:30", Undirected: true},
+		},
+	}
+	mmd := MermaidRenderer{}.RenderGraph(graph)
+
+	for _, line := range []string{
+		`title: "Flow \"A\""`,
+		"flowchart LR",
+		`  subgraph group_0["Group 1: collect"]`,
+		`    config_0_0["Config 1<br/>- step #lt;x#gt;"]`,
+		"  end",
+		`  config_0_0 -->|"a#124;b"| config_0_1`,
+		`  config_0_0 -.-|"A/B 70:30"| config_0_2`,
+	} {
+		if !strings.Contains(mmd, line+"\n") {
+			t.Errorf("Mermaid output is missing line %q:\n%s", line, mmd)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package diagram
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+	"github.com/tsocial/ui-version-mapping/pkg/condition"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+)
+
+// Format is a diagram source format
+type Format string
+
+// Supported diagram formats
+const (
+	FormatPlantUML Format = "plantuml"
+	FormatMermaid  Format = "mermaid"
+)
+
+// ParseFormats parses a comma-separated list of diagram formats, e.g. "plantuml,mermaid"
+func ParseFormats(spec string) ([]Format, error) {
+	var formats []Format
+	seen := make(map[Format]bool)
+	for _, part := range strings.Split(spec, ",") {
+		format := Format(strings.ToLower(strings.TrimSpace(part)))
+		if format == "" || seen[format] {
+			continue
+		}
+		if format != FormatPlantUML && format != FormatMermaid {
+			return nil, fmt.Errorf("unknown diagram format %q (want plantuml or mermaid)", part)
+		}
+		seen[format] = true
+		formats = append(formats, format)
+	}
+	if len(formats) == 0 {
+		return nil, fmt.Errorf("no diagram format in %q", spec)
+	}
+	return formats, nil
+}
+
+// Name returns the display name of the format
+func (f Format) Name() string {
+	switch f {
+	case FormatMermaid:
+		return "Mermaid"
+	default:
+		return "PlantUML"
+	}
+}
+
+// Extension returns the file extension of diagram sources in the format
+func (f Format) Extension() string {
+	switch f {
+	case FormatMermaid:
+		return ".mmd"
+	default:
+		return ".puml"
+	}
+}
+
+// Renderer turns diagram models into diagram source text
+type Renderer interface {
+	Format() Format
+	RenderGraph(g *Graph) string
+	RenderActivity(a *Activity) string
+}
+
+// NewRenderer returns the renderer of a format
+func NewRenderer(format Format) Renderer {
+	if format == FormatMermaid {
+		return MermaidRenderer{}
+	}
+	return PlantUMLRenderer{}
+}
+
+// Style is the semantic color of a diagram element
+type Style string
+
+// Styles shared by all renderers
+const (
+	StyleDefault Style = ""
+	StylePrimary Style = "primary"
+	StyleSuccess Style = "success"
+	StyleWarning Style = "warning"
+	StyleDanger  Style = "danger"
+	StyleInfo    Style = "info"
+)
+
+// styleColors are the Materia theme colors of the styles
+var styleColors = map[Style]string{
+	StylePrimary: "#2196F3",
+	StyleSuccess: "#4CAF50",
+	StyleWarning: "#ff9800",
+	StyleDanger:  "#e51c23",
+	StyleInfo:    "#9C27B0",
+}
+
+// Graph is a diagram of boxes connected by labelled arrows, optionally grouped
+type Graph struct {
+	Title string
+	// Groups hold boxes drawn inside a named frame
+	Groups []Group
+	// Nodes are the boxes outside any group
+	Nodes  []Node
+	Edges  []Edge
+	Legend []LegendEntry
+}
+
+// Group is a named frame around some nodes
+type Group struct {
+	Label string
+	Nodes []Node
+}
+
+// Node is a box; each label entry is a line
+type Node struct {
+	ID    string
+	Label []string
+	Style Style
+}
+
+// Edge is an arrow between two nodes; undirected edges are dashed links without arrow head
+type Edge struct {
+	From       string
+	To         string
+	Label      string
+	Undirected bool
+}
+
+// LegendEntry explains the meaning of a style
+type LegendEntry struct {
+	Style Style
+	Label string
+}
+
+// Activity is a top-down sequence of steps from start to stop
+type Activity struct {
+	Title string
+	Steps []ActivityStep
+	// Note lines are shown next to the end of the activity
+	Note []string
+}
+
+// ActivityStep is a step of an activity. A decision step is drawn as a diamond whose
+// rejected branch stops the activity. Choices are tried in order, Fallback is taken when
+// none of them applies.
+type ActivityStep struct {
+	Label    []string
+	Decision bool
+	Choices  []Choice
+	Fallback []string
+}
+
+// Choice is a branch of a step taken when its condition holds
+type Choice struct {
+	Condition string
+	Label     []string
+}
+
+// ABTestingGraph builds the diagram of A/B testing groups: one frame per group with a box per variant
+func ABTestingGraph(groups []analyzer.ABTestingGroup) *Graph {
+	graph := &Graph{Title: "A/B Testing Groups Analysis"}
+
+	for i, group := range groups {
+		frame := Group{Label: fmt.Sprintf("Group %d: %s", i+1, group.GroupName)}
+		for j, variant := range group.Variants {
+			var percentage float64
+			if group.TotalWeight > 0 {
+				percentage = float64(variant.Weight) / float64(group.TotalWeight) * 100
+			}
+			label := []string{
+				fmt.Sprintf("Config %d", variant.ConfigID),
+				fmt.Sprintf("Weight: %d (%.1f%%)", variant.Weight, percentage),
+			}
+			for _, op := range variant.FlowDiff {
+				label = append(label, op.Compact())
+			}
+			frame.Nodes = append(frame.Nodes, Node{ID: fmt.Sprintf("config_%d_%d", i, j), Label: label})
+		}
+		graph.Groups = append(graph.Groups, frame)
+	}
+
+	return graph
+}
+
+// JourneyFlowGraph builds the diagram of the journeys from a source config to its related configs.
+// Self-loops are left out for a cleaner diagram.
+func JourneyFlowGraph(template *journey.JourneyTemplate) *Graph {
+	sourceID := int(template.SearchValue)
+	graph := &Graph{
+		Title: fmt.Sprintf("Journey Flow Analysis - Config %d", sourceID),
+		Nodes: []Node{{
+			ID:    configNodeID(sourceID),
+			Label: []string{fmt.Sprintf("Config %d", sourceID), "(Source)"},
+			Style: StylePrimary,
+		}},
+		Legend: []LegendEntry{
+			{StylePrimary, "Source Config"},
+			{StyleSuccess, "Normal Flow"},
+			{StyleWarning, "Automated Flow"},
+			{StyleInfo, "Semi-Automated Flow"},
+			{StylePrimary, "CIF Verification"},
+			{StyleDanger, "Rejection Flow"},
+		},
+	}
+
+	seen := make(map[int]bool)
+	for _, j := range template.Journeys {
+		if j.ToLenderConfigID == sourceID || seen[j.ToLenderConfigID] {
+			continue
+		}
+		seen[j.ToLenderConfigID] = true
+		graph.Nodes = append(graph.Nodes, Node{
+			ID:    configNodeID(j.ToLenderConfigID),
+			Label: []string{fmt.Sprintf("Config %d", j.ToLenderConfigID), j.Description},
+			Style: flowTypeStyle(j.FlowType),
+		})
+	}
+
+	for _, j := range template.Journeys {
+		if j.FromLenderConfigID != j.ToLenderConfigID {
+			graph.Edges = append(graph.Edges, Edge{
+				From:  configNodeID(j.FromLenderConfigID),
+				To:    configNodeID(j.ToLenderConfigID),
+				Label: j.FlowType,
+			})
+		}
+	}
+
+	return graph
+}
+
+// JourneyStepsActivity builds the diagram of the steps of a journey with their UI versions.
+// Steps with conditional UI versions branch on each condition.
+func JourneyStepsActivity(j journey.Journey) *Activity {
+	activity := &Activity{Title: fmt.Sprintf("Journey Steps - %s - %s", j.ID, j.Description)}
+
+	for _, step := range j.Steps {
+		stepLabel := fmt.Sprintf("Step %d: %s", step.ID, step.Name)
+		item := ActivityStep{Decision: step.Decision}
+
+		if len(step.SubUIVersionByConditions) > 0 {
+			item.Label = []string{stepLabel}
+			for _, cond := range step.SubUIVersionByConditions {
+				item.Choices = append(item.Choices, Choice{
+					// Render operators as words
+					Condition: condition.DescribeString(cond.Condition),
+					Label:     []string{"Use UI Version", cond.SubUIVersion},
+				})
+			}
+			item.Fallback = []string{"Use UI Version", step.MainUIVersion}
+			if step.SubUIVersion != "" {
+				item.Fallback = []string{"Use UI Version", step.SubUIVersion, fmt.Sprintf("(Main: %s)", step.MainUIVersion)}
+			}
+		} else if step.SubUIVersion != "" {
+			// Sub UI version takes priority as the main display
+			item.Label = []string{stepLabel, fmt.Sprintf("UI Version: %s", step.SubUIVersion), fmt.Sprintf("(Main: %s)", step.MainUIVersion)}
+		} else {
+			item.Label = []string{stepLabel, fmt.Sprintf("UI Version: %s", step.MainUIVersion)}
+		}
+
+		activity.Steps = append(activity.Steps, item)
+	}
+
+	activity.Note = []string{
+		"Journey Information:",
+		fmt.Sprintf("Flow Type: %s", j.FlowType),
+		fmt.Sprintf("From Config: %d", j.FromLenderConfigID),
+		fmt.Sprintf("To Config: %d", j.ToLenderConfigID),
+	}
+	if j.Condition != "" {
+		activity.Note = append(activity.Note, fmt.Sprintf("Condition: %s", j.Condition))
+	}
+	activity.Note = append(activity.Note,
+		"",
+		"UI Version Legend:",
+		"- Main UI: Primary version",
+		"- Sub UI: Secondary version",
+		"- Conditional: Dynamic based on conditions",
+		"- Diamond: Decision step, rejection ends the journey",
+	)
+
+	return activity
+}
+
+// WriteGraph renders a graph with r and writes it to filename
+func WriteGraph(g *Graph, r Renderer, filename string) error {
+	return writeSource(r.RenderGraph(g), r.Format(), filename)
+}
+
+// WriteActivity renders an activity with r and writes it to filename
+func WriteActivity(a *Activity, r Renderer, filename string) error {
+	return writeSource(r.RenderActivity(a), r.Format(), filename)
+}
+
+func writeSource(source string, format Format, filename string) error {
+	if err := ensureDir(filename); err != nil {
+		return fmt.Errorf("failed to prepare file path: %w", err)
+	}
+	if err := os.WriteFile(filename, []byte(source), 0644); err != nil {
+		return fmt.Errorf("failed to write %s file %s: %w", format.Name(), filename, err)
+	}
+	return nil
+}
+
+// flowTypeStyle colors a journey target by flow type
+func flowTypeStyle(flowType string) Style {
+	switch {
+	case strings.Contains(flowType, "rejection"):
+		return StyleDanger
+	case strings.Contains(flowType, "auto"):
+		return StyleWarning
+	case strings.Contains(flowType, "semi"):
+		return StyleInfo
+	case strings.Contains(flowType, "cif"):
+		return StylePrimary
+	default:
+		return StyleSuccess
+	}
+}
+
+func configNodeID(configID int) string {
+	return fmt.Sprintf("config_%d", configID)
+}
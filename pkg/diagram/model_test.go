@@ -0,0 +1,110 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+)
+
+func testTemplate() *journey.JourneyTemplate {
+	return &journey.JourneyTemplate{
+		SearchValue: 9054,
+		Journeys: []journey.Journey{
+			{ID: "from_9054_to_9054", FlowType: "normal", FromLenderConfigID: 9054, ToLenderConfigID: 9054},
+			{ID: "from_9054_to_9012", FlowType: "collect_to_rejection", Description: "Rejected", FromLenderConfigID: 9054, ToLenderConfigID: 9012},
+			{ID: "from_9054_to_9013", FlowType: "collect_to_auto", Description: "Automated", FromLenderConfigID: 9054, ToLenderConfigID: 9013},
+		},
+	}
+}
+
+func testJourney() journey.Journey {
+	return journey.Journey{
+		ID:                 "from_9054_to_9012",
+		FlowType:           "collect_to_rejection",
+		Description:        "Rejected",
+		FromLenderConfigID: 9054,
+		ToLenderConfigID:   9012,
+		Steps: []journey.Step{
+			{ID: 0, Name: "otp", MainUIVersion: "v1"},
+			{ID: 1, Name: "esign.review", MainUIVersion: "v1", SubUIVersion: "v1.0-auto", SubUIVersionByConditions: []journey.SubUIVersionByCondition{
+				{Condition: "telco_code=viettel", SubUIVersion: "v1.1-auto"},
+				{Condition: "telco_code=mobifone", SubUIVersion: "v1.2-auto"},
+			}},
+			{ID: 2, Name: "appraising.cif", MainUIVersion: "v2", Decision: true},
+		},
+	}
+}
+
+func TestJourneyFlowGraph(t *testing.T) {
+	graph := JourneyFlowGraph(testTemplate())
+
+	if len(graph.Nodes) != 3 || graph.Nodes[1].Style != StyleDanger || graph.Nodes[2].Style != StyleWarning {
+		t.Errorf("unexpected nodes %+v", graph.Nodes)
+	}
+	if len(graph.Edges) != 2 || graph.Edges[0] != (Edge{From: "config_9054", To: "config_9012", Label: "collect_to_rejection"}) {
+		t.Errorf("expected self-loops to be skipped, got %+v", graph.Edges)
+	}
+}
+
+func TestABTestingGraph(t *testing.T) {
+	groups := []analyzer.ABTestingGroup{{
+		GroupName:   "collect",
+		TotalWeight: 100,
+		Variants: []analyzer.ABTestingVariant{
+			{ConfigID: 9054, Weight: 60},
+			{ConfigID: 9055, Weight: 40},
+		},
+	}}
+
+	graph := ABTestingGraph(groups)
+	if len(graph.Groups) != 1 || graph.Groups[0].Label != "Group 1: collect" || len(graph.Groups[0].Nodes) != 2 {
+		t.Fatalf("unexpected groups %+v", graph.Groups)
+	}
+	if label := strings.Join(graph.Groups[0].Nodes[1].Label, "|"); label != "Config 9055|Weight: 40 (40.0%)" {
+		t.Errorf("unexpected variant label %q", label)
+	}
+}
+
+func TestRenderersProduceEquivalentDiagrams(t *testing.T) {
+	graph := JourneyFlowGraph(testTemplate())
+	activity := JourneyStepsActivity(testJourney())
+
+	// Every box and branch must show up in both formats
+	want := []string{"Config 9012", "Rejected", "collect_to_auto", "Step 0: otp", "v1.1-auto", "v1.2-auto", "Step 2: appraising.cif", "rejected"}
+	for _, renderer := range []Renderer{PlantUMLRenderer{}, MermaidRenderer{}} {
+		source := renderer.RenderGraph(graph) + renderer.RenderActivity(activity)
+		for _, text := range want {
+			if !strings.Contains(source, text) {
+				t.Errorf("%s output is missing %q", renderer.Format().Name(), text)
+			}
+		}
+	}
+
+	puml := PlantUMLRenderer{}.RenderActivity(activity)
+	for _, line := range []string{
+		"if (telco_code equals viettel?) then (yes)",
+		"elseif (telco_code equals mobifone?) then (yes)",
+		"  :Use UI Version\\nv1.0-auto\\n(Main: v1);",
+		"if (Step 2: appraising.cif\\nUI Version: v2) then (approved)",
+	} {
+		if !strings.Contains(puml, line+"\n") {
+			t.Errorf("PlantUML output is missing line %q:\n%s", line, puml)
+		}
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	formats, err := ParseFormats("mermaid, PlantUML,mermaid")
+	if err != nil {
+		t.Fatalf("ParseFormats failed: %v", err)
+	}
+	if len(formats) != 2 || formats[0] != FormatMermaid || formats[1] != FormatPlantUML {
+		t.Errorf("unexpected formats %v", formats)
+	}
+
+	if _, err := ParseFormats("svg"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
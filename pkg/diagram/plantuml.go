@@ -30,61 +30,48 @@ type ActivityDiagram struct {
 
 // GenerateABTestingDiagram creates PlantUML diagram for A/B testing groups
 func GenerateABTestingDiagram(groups []analyzer.ABTestingGroup, filename string) error {
-	var puml strings.Builder
-
-	puml.WriteString("@startuml\n")
-	puml.WriteString("title A/B Testing Groups Analysis\n\n")
-
-	for i, group := range groups {
-		puml.WriteString(fmt.Sprintf("package \"Group %d: %s\" {\n", i+1, group.GroupName))
-
-		for j, variant := range group.Variants {
-			percentage := float64(variant.Weight) / float64(group.TotalWeight) * 100
-			puml.WriteString(fmt.Sprintf("  rectangle \"Config %d\\nWeight: %d (%.1f%%)\" as config_%d_%d\n",
-				variant.ConfigID, variant.Weight, percentage, i, j))
-		}
-
-		puml.WriteString("}\n\n")
+	if err := WriteGraph(ABTestingGraph(groups), PlantUMLRenderer{}, filename); err != nil {
+		return err
 	}
 
-	puml.WriteString("@enduml\n")
+	fmt.Printf("A/B Testing PlantUML diagram written to %s\n", filename)
+	return nil
+}
 
-	// Write to file
-	if err := ensureDir(filename); err != nil {
-		return fmt.Errorf("failed to prepare file path: %w", err)
+// GenerateJourneyFlowDiagram creates a PlantUML diagram for journey flows
+func GenerateJourneyFlowDiagram(template *journey.JourneyTemplate, filename string) error {
+	if err := WriteGraph(JourneyFlowGraph(template), PlantUMLRenderer{}, filename); err != nil {
+		return err
 	}
 
-	err := os.WriteFile(filename, []byte(puml.String()), 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write PlantUML file %s: %w", filename, err)
+	fmt.Printf("Journey flow PlantUML diagram written to %s\n", filename)
+	return nil
+}
+
+// GenerateJourneyStepsDiagram creates a PlantUML diagram for an individual journey showing UI versions with branching
+func GenerateJourneyStepsDiagram(j journey.Journey, filename string) error {
+	if err := WriteActivity(JourneyStepsActivity(j), PlantUMLRenderer{}, filename); err != nil {
+		return err
 	}
 
-	fmt.Printf("A/B Testing PlantUML diagram written to %s\n", filename)
+	fmt.Printf("Journey steps PlantUML diagram written to %s\n", filename)
 	return nil
 }
 
-// GenerateJourneyFlowDiagram creates a PlantUML diagram for journey flows
-func GenerateJourneyFlowDiagram(template *journey.JourneyTemplate, filename string) error {
-	var puml strings.Builder
+// PlantUMLRenderer renders diagrams as PlantUML with the Materia theme
+type PlantUMLRenderer struct{}
 
-	puml.WriteString("@startuml\n")
+// Format returns FormatPlantUML
+func (PlantUMLRenderer) Format() Format {
+	return FormatPlantUML
+}
 
-	// Add Materia theme
-	puml.WriteString("!$THEME = \"materia\"\n\n")
-	puml.WriteString("!if %not(%variable_exists(\"$BGCOLOR\"))\n")
-	puml.WriteString("!$BGCOLOR = \"transparent\"\n")
-	puml.WriteString("!endif\n\n")
-	puml.WriteString("skinparam backgroundColor $BGCOLOR\n")
-	puml.WriteString("skinparam useBetaStyle false\n\n")
+// RenderGraph renders a graph as rectangles, packages for groups and arrows
+func (PlantUMLRenderer) RenderGraph(g *Graph) string {
+	var puml strings.Builder
 
-	// Define colors
-	puml.WriteString("!$PRIMARY = \"#2196F3\"\n")
-	puml.WriteString("!$SUCCESS = \"#4CAF50\"\n")
-	puml.WriteString("!$WARNING = \"#ff9800\"\n")
-	puml.WriteString("!$DANGER = \"#e51c23\"\n")
-	puml.WriteString("!$INFO = \"#9C27B0\"\n")
-	puml.WriteString("!$WHITE = \"#FFF\"\n")
-	puml.WriteString("!$DARK = \"#222\"\n\n")
+	puml.WriteString("@startuml\n")
+	writePlantUMLTheme(&puml)
 
 	// Apply styling
 	puml.WriteString("skinparam rectangle {\n")
@@ -100,77 +87,161 @@ func GenerateJourneyFlowDiagram(template *journey.JourneyTemplate, filename stri
 	puml.WriteString("  Thickness 2\n")
 	puml.WriteString("}\n\n")
 
-	puml.WriteString(fmt.Sprintf("title Journey Flow Analysis - Config %d\n\n", template.SearchValue))
-
-	// Define the source config
-	puml.WriteString(fmt.Sprintf("rectangle \"Config %d\\n(Source)\" as config_%d $PRIMARY\n",
-		template.SearchValue, template.SearchValue))
-
-	// Define target configs
-	configMap := make(map[int]bool)
-	for _, j := range template.Journeys {
-		if j.ToLenderConfigID != int(template.SearchValue) && !configMap[j.ToLenderConfigID] {
-			configMap[j.ToLenderConfigID] = true
-
-			// Determine color based on flow type using theme colors
-			color := "$SUCCESS"
-			if strings.Contains(j.FlowType, "rejection") {
-				color = "$DANGER"
-			} else if strings.Contains(j.FlowType, "auto") {
-				color = "$WARNING"
-			} else if strings.Contains(j.FlowType, "semi") {
-				color = "$INFO"
-			} else if strings.Contains(j.FlowType, "cif") {
-				color = "$PRIMARY"
-			}
+	puml.WriteString(fmt.Sprintf("title %s\n\n", g.Title))
 
-			puml.WriteString(fmt.Sprintf("rectangle \"Config %d\\n%s\" as config_%d %s\n",
-				j.ToLenderConfigID, j.Description, j.ToLenderConfigID, color))
+	for _, group := range g.Groups {
+		puml.WriteString(fmt.Sprintf("package \"%s\" {\n", group.Label))
+		for _, node := range group.Nodes {
+			puml.WriteString("  " + plantUMLRectangle(node))
 		}
+		puml.WriteString("}\n\n")
 	}
 
-	puml.WriteString("\n")
+	for _, node := range g.Nodes {
+		puml.WriteString(plantUMLRectangle(node))
+	}
 
-	// Add journey connections
-	for _, j := range template.Journeys {
-		if j.FromLenderConfigID != j.ToLenderConfigID {
-			// Connection to other configs only (skip self-loops for cleaner diagram)
-			label := j.FlowType
-			if j.Condition != "" {
-				// Escape condition text for PlantUML
-				label = j.FlowType // Simplified label
+	if len(g.Edges) > 0 {
+		puml.WriteString("\n")
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Undirected {
+			arrow = ".."
+		}
+		if edge.Label != "" {
+			puml.WriteString(fmt.Sprintf("%s %s %s : %s\n", edge.From, arrow, edge.To, edge.Label))
+		} else {
+			puml.WriteString(fmt.Sprintf("%s %s %s\n", edge.From, arrow, edge.To))
+		}
+	}
+
+	if len(g.Legend) > 0 {
+		puml.WriteString("\nlegend right\n")
+		puml.WriteString("  |Color|Meaning|\n")
+		for _, entry := range g.Legend {
+			puml.WriteString(fmt.Sprintf("  |<%s>|%s|\n", plantUMLColor(entry.Style), entry.Label))
+		}
+		puml.WriteString("endlegend\n")
+	}
+
+	puml.WriteString("\n@enduml\n")
+	return puml.String()
+}
+
+// RenderActivity renders an activity diagram; decision steps and choices become if/else branches
+func (PlantUMLRenderer) RenderActivity(a *Activity) string {
+	var puml strings.Builder
+
+	puml.WriteString("@startuml\n")
+	writePlantUMLTheme(&puml)
+
+	// Apply activity styling
+	puml.WriteString("skinparam activity {\n")
+	puml.WriteString("  BackgroundColor $PRIMARY\n")
+	puml.WriteString("  BorderColor $BLUE\n")
+	puml.WriteString("  FontColor $WHITE\n")
+	puml.WriteString("  StartColor $SUCCESS\n")
+	puml.WriteString("  EndColor $DANGER\n")
+	puml.WriteString("  DiamondBackgroundColor $WARNING\n")
+	puml.WriteString("  DiamondBorderColor $ORANGE\n")
+	puml.WriteString("  DiamondFontColor $DARK\n")
+	puml.WriteString("}\n\n")
+
+	puml.WriteString("skinparam arrow {\n")
+	puml.WriteString("  Color $PRIMARY\n")
+	puml.WriteString("  FontColor $DARK\n")
+	puml.WriteString("  Thickness 2\n")
+	puml.WriteString("}\n\n")
+
+	puml.WriteString(fmt.Sprintf("title %s\n\n", a.Title))
+	puml.WriteString("start\n")
+
+	for i, step := range a.Steps {
+		text := plantUMLText(step.Label)
+		if step.Decision {
+			// Decision steps are diamonds whose rejected branch stops the journey
+			puml.WriteString(fmt.Sprintf("if (%s) then (approved)\n", text))
+			puml.WriteString("else (rejected)\n")
+			puml.WriteString("  stop\n")
+			puml.WriteString("endif\n")
+		} else {
+			puml.WriteString(fmt.Sprintf(":%s;\n", text))
+		}
+
+		for k, choice := range step.Choices {
+			keyword := "elseif"
+			if k == 0 {
+				keyword = "if"
 			}
+			puml.WriteString(fmt.Sprintf("%s (%s?) then (yes)\n", keyword, choice.Condition))
+			puml.WriteString(fmt.Sprintf("  :%s;\n", plantUMLText(choice.Label)))
+		}
+		if len(step.Choices) > 0 {
+			puml.WriteString("else (no)\n")
+			puml.WriteString(fmt.Sprintf("  :%s;\n", plantUMLText(step.Fallback)))
+			puml.WriteString("endif\n")
+		}
 
-			puml.WriteString(fmt.Sprintf("config_%d --> config_%d : %s\n",
-				j.FromLenderConfigID, j.ToLenderConfigID, label))
+		// Add separator between steps (except for last step)
+		if i < len(a.Steps)-1 {
+			puml.WriteString("\n")
 		}
 	}
 
-	// Add legend
-	puml.WriteString("\nlegend right\n")
-	puml.WriteString("  |Color|Flow Type|\n")
-	puml.WriteString("  |<#lightblue>|Source Config|\n")
-	puml.WriteString("  |<#lightgreen>|Normal Flow|\n")
-	puml.WriteString("  |<#lightyellow>|Automated Flow|\n")
-	puml.WriteString("  |<#lightpink>|Semi-Automated Flow|\n")
-	puml.WriteString("  |<#lightcyan>|CIF Verification|\n")
-	puml.WriteString("  |<#lightcoral>|Rejection Flow|\n")
-	puml.WriteString("endlegend\n")
+	puml.WriteString("\nstop\n")
+
+	if len(a.Note) > 0 {
+		puml.WriteString("\nnote right\n")
+		for _, line := range a.Note {
+			puml.WriteString(line + "\n")
+		}
+		puml.WriteString("end note\n")
+	}
 
 	puml.WriteString("\n@enduml\n")
+	return puml.String()
+}
+
+// writePlantUMLTheme writes the Materia theme and its color variables
+func writePlantUMLTheme(puml *strings.Builder) {
+	puml.WriteString("!$THEME = \"materia\"\n\n")
+	puml.WriteString("!if %not(%variable_exists(\"$BGCOLOR\"))\n")
+	puml.WriteString("!$BGCOLOR = \"transparent\"\n")
+	puml.WriteString("!endif\n\n")
+	puml.WriteString("skinparam backgroundColor $BGCOLOR\n")
+	puml.WriteString("skinparam useBetaStyle false\n\n")
 
-	// Write to file
-	if err := ensureDir(filename); err != nil {
-		return fmt.Errorf("failed to prepare file path: %w", err)
+	// Define colors
+	puml.WriteString("!$BLUE = \"#2196F3\"\n")
+	puml.WriteString("!$ORANGE = \"#fd7e14\"\n")
+	puml.WriteString(fmt.Sprintf("!$PRIMARY = \"%s\"\n", styleColors[StylePrimary]))
+	puml.WriteString(fmt.Sprintf("!$SUCCESS = \"%s\"\n", styleColors[StyleSuccess]))
+	puml.WriteString(fmt.Sprintf("!$WARNING = \"%s\"\n", styleColors[StyleWarning]))
+	puml.WriteString(fmt.Sprintf("!$DANGER = \"%s\"\n", styleColors[StyleDanger]))
+	puml.WriteString(fmt.Sprintf("!$INFO = \"%s\"\n", styleColors[StyleInfo]))
+	puml.WriteString("!$WHITE = \"#FFF\"\n")
+	puml.WriteString("!$DARK = \"#222\"\n\n")
+}
+
+func plantUMLRectangle(node Node) string {
+	if node.Style == StyleDefault {
+		return fmt.Sprintf("rectangle \"%s\" as %s\n", plantUMLText(node.Label), node.ID)
 	}
+	return fmt.Sprintf("rectangle \"%s\" as %s %s\n", plantUMLText(node.Label), node.ID, plantUMLColor(node.Style))
+}
 
-	err := os.WriteFile(filename, []byte(puml.String()), 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write PlantUML file %s: %w", filename, err)
+// plantUMLColor returns the theme variable of a style
+func plantUMLColor(style Style) string {
+	if style == StyleDefault {
+		return "$PRIMARY"
 	}
+	return "$" + strings.ToUpper(string(style))
+}
 
-	fmt.Printf("Journey flow PlantUML diagram written to %s\n", filename)
-	return nil
+// plantUMLText joins label lines with PlantUML line breaks
+func plantUMLText(lines []string) string {
+	return strings.Join(lines, "\\n")
 }
 
 // ExportPlantUMLToPNG converts a PlantUML file to PNG using plantuml.jar
@@ -0,0 +1,35 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlantUMLActivityBranches(t *testing.T) {
+	puml := PlantUMLRenderer{}.RenderActivity(JourneyStepsActivity(testJourney()))
+
+	// Conditional UI versions form a single if/elseif/else chain
+	for _, fragment := range []string{
+		"if (telco_code equals viettel?) then (yes)\n",
+		"elseif (telco_code equals mobifone?) then (yes)\n",
+		"else (no)\n",
+	} {
+		if strings.Count(puml, fragment) != 1 {
+			t.Errorf("expected %q once in:\n%s", fragment, puml)
+		}
+	}
+
+	// Every if is closed exactly once: the choices and the decision step
+	var ifs, endifs int
+	for _, line := range strings.Split(puml, "\n") {
+		switch {
+		case strings.HasPrefix(line, "if ("):
+			ifs++
+		case line == "endif":
+			endifs++
+		}
+	}
+	if ifs != 2 || endifs != 2 {
+		t.Errorf("expected 2 balanced if/endif blocks, got %d if and %d endif:\n%s", ifs, endifs, puml)
+	}
+}
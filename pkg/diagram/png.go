@@ -0,0 +1,236 @@
+package diagram
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PNGScale is the number of pixels per image unit used by WritePNG
+const PNGScale = 2
+
+// Rasterize draws the image at scale pixels per unit. Text is drawn with a built-in 5x7
+// bitmap font; characters outside ASCII are drawn as '?'.
+func (img *Image) Rasterize(scale int) *image.RGBA {
+	c := &canvas{
+		img:   image.NewRGBA(image.Rect(0, 0, int(math.Ceil(img.Width*float64(scale))), int(math.Ceil(img.Height*float64(scale))))),
+		scale: float64(scale),
+	}
+	c.fillRect(rect{0, 0, img.Width, img.Height}, parseColor(paperColor))
+
+	for _, s := range img.shapes {
+		switch s.kind {
+		case shapeRect:
+			if s.fill != "" {
+				c.fillRect(s.bounds, parseColor(s.fill))
+			}
+			if s.stroke != "" {
+				b := s.bounds
+				c.polyline([]point{{b.X, b.Y}, {b.X + b.W, b.Y}, {b.X + b.W, b.bottom()}, {b.X, b.bottom()}, {b.X, b.Y}}, parseColor(s.stroke), s.dashed)
+			}
+		case shapeDiamond:
+			center := s.bounds.center()
+			corners := []point{{center.X, s.bounds.Y}, {s.bounds.X + s.bounds.W, center.Y}, {center.X, s.bounds.bottom()}, {s.bounds.X, center.Y}}
+			if s.fill != "" {
+				c.fillPolygon(corners, parseColor(s.fill))
+			}
+			if s.stroke != "" {
+				c.polyline(append(corners, corners[0]), parseColor(s.stroke), s.dashed)
+			}
+		case shapeCircle:
+			c.circle(s.bounds, s.fill, s.stroke)
+		case shapePolygon:
+			c.fillPolygon(s.points, parseColor(s.fill))
+		case shapeLine:
+			c.polyline(s.points, parseColor(s.stroke), s.dashed)
+		case shapeText:
+			c.text(s)
+		}
+	}
+
+	return c.img
+}
+
+// EncodePNG rasterises the image at PNGScale and writes it as PNG to w
+func (img *Image) EncodePNG(w io.Writer) error {
+	return png.Encode(w, img.Rasterize(PNGScale))
+}
+
+// WritePNG rasterises the image at PNGScale and writes it to filename
+func (img *Image) WritePNG(filename string) error {
+	if err := ensureDir(filename); err != nil {
+		return fmt.Errorf("failed to prepare PNG output path: %w", err)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create PNG file %s: %w", filename, err)
+	}
+	if err := img.EncodePNG(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode PNG file %s: %w", filename, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write PNG file %s: %w", filename, err)
+	}
+	return nil
+}
+
+// canvas draws shapes given in image units onto pixels
+type canvas struct {
+	img   *image.RGBA
+	scale float64
+}
+
+// fill sets the pixels of bounds whose center, in image units, is inside
+func (c *canvas) fill(bounds rect, col color.RGBA, inside func(p point) bool) {
+	area := image.Rect(
+		int(math.Floor(bounds.X*c.scale)), int(math.Floor(bounds.Y*c.scale)),
+		int(math.Ceil((bounds.X+bounds.W)*c.scale)), int(math.Ceil(bounds.bottom()*c.scale)),
+	).Intersect(c.img.Bounds())
+	for py := area.Min.Y; py < area.Max.Y; py++ {
+		for px := area.Min.X; px < area.Max.X; px++ {
+			if inside(point{(float64(px) + 0.5) / c.scale, (float64(py) + 0.5) / c.scale}) {
+				c.img.SetRGBA(px, py, col)
+			}
+		}
+	}
+}
+
+func (c *canvas) fillRect(bounds rect, col color.RGBA) {
+	c.fill(bounds, col, func(point) bool { return true })
+}
+
+func (c *canvas) fillPolygon(points []point, col color.RGBA) {
+	bounds := pointBounds(points)
+	c.fill(bounds, col, func(p point) bool {
+		// Even-odd rule
+		inside := false
+		for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+			a, b := points[i], points[j]
+			if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+				inside = !inside
+			}
+		}
+		return inside
+	})
+}
+
+// circle fills the circle inscribed in bounds and strokes its outline
+func (c *canvas) circle(bounds rect, fill, stroke string) {
+	center, radius := bounds.center(), bounds.W/2
+	if fill != "" {
+		c.fill(bounds, parseColor(fill), func(p point) bool {
+			return math.Hypot(p.X-center.X, p.Y-center.Y) <= radius
+		})
+	}
+	if stroke != "" {
+		ring := rect{bounds.X - 1, bounds.Y - 1, bounds.W + 2, bounds.H + 2}
+		c.fill(ring, parseColor(stroke), func(p point) bool {
+			return math.Abs(math.Hypot(p.X-center.X, p.Y-center.Y)-radius) <= 0.75
+		})
+	}
+}
+
+// polyline strokes the segments between points; dashed lines alternate 6 units drawn and 4 skipped
+func (c *canvas) polyline(points []point, col color.RGBA, dashed bool) {
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		if !dashed {
+			c.segment(a, b, col)
+			continue
+		}
+		length := math.Hypot(b.X-a.X, b.Y-a.Y)
+		for from := 0.0; from < length; from += 10 {
+			to := math.Min(from+6, length)
+			c.segment(
+				point{a.X + (b.X-a.X)*from/length, a.Y + (b.Y-a.Y)*from/length},
+				point{a.X + (b.X-a.X)*to/length, a.Y + (b.Y-a.Y)*to/length},
+				col,
+			)
+		}
+	}
+}
+
+// segment strokes a line 1.5 units wide
+func (c *canvas) segment(a, b point, col color.RGBA) {
+	const halfWidth = 0.75
+	bounds := pointBounds([]point{a, b})
+	bounds = rect{bounds.X - halfWidth, bounds.Y - halfWidth, bounds.W + 2*halfWidth, bounds.H + 2*halfWidth}
+	dx, dy := b.X-a.X, b.Y-a.Y
+	lengthSq := dx*dx + dy*dy
+	c.fill(bounds, col, func(p point) bool {
+		t := 0.0
+		if lengthSq > 0 {
+			t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/lengthSq))
+		}
+		return math.Hypot(p.X-(a.X+t*dx), p.Y-(a.Y+t*dy)) <= halfWidth
+	})
+}
+
+// text draws the lines of a text shape laid out like writeSVGText; bold text has wider strokes
+func (c *canvas) text(s shape) {
+	col := parseColor(s.fill)
+	pixel := int(c.scale)
+	top := s.bounds.center().Y - float64(len(s.lines))*imageLineHeight/2
+	for i, line := range s.lines {
+		runes := []rune(line)
+		x := s.bounds.center().X - float64(len(runes))*imageCharWidth/2
+		if s.leftAlign {
+			x = s.bounds.X
+		}
+		y := top + float64(i)*imageLineHeight + (imageLineHeight-glyphHeight)/2
+
+		for k, r := range runes {
+			left := int(math.Round((x + float64(k)*imageCharWidth + 1) * c.scale))
+			upper := int(math.Round(y * c.scale))
+			bitmap := glyph(r)
+			for column, bits := range bitmap {
+				for row := 0; row < glyphHeight; row++ {
+					if bits&(1<<row) == 0 {
+						continue
+					}
+					dot := image.Rect(left+column*pixel, upper+row*pixel, left+(column+1)*pixel, upper+(row+1)*pixel)
+					if s.bold {
+						dot.Max.X++
+					}
+					c.dot(dot, col)
+				}
+			}
+		}
+	}
+}
+
+func (c *canvas) dot(area image.Rectangle, col color.RGBA) {
+	area = area.Intersect(c.img.Bounds())
+	for py := area.Min.Y; py < area.Max.Y; py++ {
+		for px := area.Min.X; px < area.Max.X; px++ {
+			c.img.SetRGBA(px, py, col)
+		}
+	}
+}
+
+func pointBounds(points []point) rect {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+		maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+	}
+	return rect{minX, minY, maxX - minX, maxY - minY}
+}
+
+// parseColor parses a "#RRGGBB" color; anything else is drawn in ink
+func parseColor(hex string) color.RGBA {
+	value, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil || len(hex) != 7 {
+		value, _ = strconv.ParseUint(inkColor[1:], 16, 32)
+	}
+	return color.RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xFF}
+}
@@ -0,0 +1,80 @@
+package diagram
+
+import (
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRasterize(t *testing.T) {
+	img := GraphImage(&Graph{
+		Title: "Flow",
+		Nodes: []Node{{ID: "a", Label: []string{"Config 1"}, Style: StyleSuccess}},
+	})
+	raster := img.Rasterize(2)
+
+	if bounds := raster.Bounds(); bounds.Dx() != int(img.Width)*2 || bounds.Dy() != int(img.Height)*2 {
+		t.Fatalf("expected a %vx%v image at scale 2, got %v", img.Width, img.Height, bounds)
+	}
+
+	paper, green := parseColor(paperColor), parseColor(styleColors[StyleSuccess])
+	if got := raster.RGBAAt(0, 0); got != paper {
+		t.Errorf("expected a paper background, got %v", got)
+	}
+
+	// The box is filled with its style color and holds white text
+	box := shapesOf(img, shapeRect)[0].bounds
+	if got := raster.RGBAAt(int(box.X*2)+4, int(box.Y*2)+4); got != green {
+		t.Errorf("expected the box filled with %v, got %v", green, got)
+	}
+	counts := make(map[color.RGBA]int)
+	for y := int(box.Y * 2); y < int(box.bottom()*2); y++ {
+		for x := int(box.X * 2); x < int((box.X+box.W)*2); x++ {
+			counts[raster.RGBAAt(x, y)]++
+		}
+	}
+	if counts[parseColor(paperColor)] == 0 {
+		t.Error("expected the label to be drawn in white")
+	}
+}
+
+func TestGlyph(t *testing.T) {
+	if glyph('A') != [glyphWidth]byte{0x7E, 0x11, 0x11, 0x11, 0x7E} {
+		t.Errorf("unexpected glyph for A: %v", glyph('A'))
+	}
+	if glyph('ế') != glyph('?') || glyph('\n') != glyph('?') {
+		t.Error("expected characters outside ASCII to be drawn as ?")
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	if got := parseColor("#2196F3"); got != (color.RGBA{0x21, 0x96, 0xF3, 0xFF}) {
+		t.Errorf("unexpected color %v", got)
+	}
+	if got := parseColor("blue"); got != parseColor(inkColor) {
+		t.Errorf("expected ink for an invalid color, got %v", got)
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "images", "steps.png")
+	img := ActivityImage(JourneyStepsActivity(testJourney()))
+	if err := img.WritePNG(filename); err != nil {
+		t.Fatalf("WritePNG: %v", err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	config, err := png.DecodeConfig(file)
+	if err != nil {
+		t.Fatalf("invalid PNG: %v", err)
+	}
+	if config.Width != int(img.Width)*PNGScale || config.Height != int(img.Height)*PNGScale {
+		t.Errorf("expected %vx%v at scale %d, got %dx%d", img.Width, img.Height, PNGScale, config.Width, config.Height)
+	}
+}
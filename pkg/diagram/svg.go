@@ -0,0 +1,114 @@
+package diagram
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SVG renders the image as a standalone SVG document. Text uses a monospace font so it
+// fits the boxes measured by the layout.
+func (img *Image) SVG() string {
+	var svg strings.Builder
+
+	svg.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="monospace" font-size="%d">`+"\n",
+		svgNumber(img.Width), svgNumber(img.Height), svgNumber(img.Width), svgNumber(img.Height), imageFontSize))
+	if img.Title != "" {
+		svg.WriteString(fmt.Sprintf("  <title>%s</title>\n", svgEscape(img.Title)))
+	}
+	svg.WriteString(fmt.Sprintf(`  <rect width="100%%" height="100%%" fill="%s"/>`+"\n", paperColor))
+
+	for _, s := range img.shapes {
+		switch s.kind {
+		case shapeRect:
+			svg.WriteString(fmt.Sprintf(`  <rect x="%s" y="%s" width="%s" height="%s"%s/>`+"\n",
+				svgNumber(s.bounds.X), svgNumber(s.bounds.Y), svgNumber(s.bounds.W), svgNumber(s.bounds.H), svgPaint(s)))
+		case shapeDiamond:
+			c := s.bounds.center()
+			svg.WriteString(fmt.Sprintf(`  <polygon points="%s"%s/>`+"\n", svgPoints([]point{
+				{c.X, s.bounds.Y}, {s.bounds.X + s.bounds.W, c.Y}, {c.X, s.bounds.bottom()}, {s.bounds.X, c.Y},
+			}), svgPaint(s)))
+		case shapeCircle:
+			c := s.bounds.center()
+			svg.WriteString(fmt.Sprintf(`  <circle cx="%s" cy="%s" r="%s"%s/>`+"\n",
+				svgNumber(c.X), svgNumber(c.Y), svgNumber(s.bounds.W/2), svgPaint(s)))
+		case shapePolygon:
+			svg.WriteString(fmt.Sprintf(`  <polygon points="%s"%s/>`+"\n", svgPoints(s.points), svgPaint(s)))
+		case shapeLine:
+			svg.WriteString(fmt.Sprintf(`  <polyline points="%s"%s/>`+"\n", svgPoints(s.points), svgPaint(s)))
+		case shapeText:
+			writeSVGText(&svg, s)
+		}
+	}
+
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+// WriteSVG writes the image as SVG to filename
+func (img *Image) WriteSVG(filename string) error {
+	if err := ensureDir(filename); err != nil {
+		return fmt.Errorf("failed to prepare file path: %w", err)
+	}
+	if err := os.WriteFile(filename, []byte(img.SVG()), 0644); err != nil {
+		return fmt.Errorf("failed to write SVG file %s: %w", filename, err)
+	}
+	return nil
+}
+
+// writeSVGText writes one text element per line, vertically centered in the bounds
+func writeSVGText(svg *strings.Builder, s shape) {
+	x, anchor := s.bounds.center().X, "middle"
+	if s.leftAlign {
+		x, anchor = s.bounds.X, "start"
+	}
+	weight := ""
+	if s.bold {
+		weight = ` font-weight="bold"`
+	}
+	top := s.bounds.center().Y - float64(len(s.lines))*imageLineHeight/2
+	for i, line := range s.lines {
+		if line == "" {
+			continue
+		}
+		baseline := top + float64(i)*imageLineHeight + imageLineHeight/2 + imageFontSize/3
+		svg.WriteString(fmt.Sprintf(`  <text x="%s" y="%s" text-anchor="%s" fill="%s"%s xml:space="preserve">%s</text>`+"\n",
+			svgNumber(x), svgNumber(baseline), anchor, s.fill, weight, svgEscape(line)))
+	}
+}
+
+// svgPaint returns the fill and stroke attributes of a shape
+func svgPaint(s shape) string {
+	fill := s.fill
+	if fill == "" {
+		fill = "none"
+	}
+	paint := fmt.Sprintf(` fill="%s"`, fill)
+	if s.stroke != "" {
+		paint += fmt.Sprintf(` stroke="%s" stroke-width="1.5"`, s.stroke)
+		if s.dashed {
+			paint += ` stroke-dasharray="6 4"`
+		}
+	}
+	return paint
+}
+
+func svgPoints(points []point) string {
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = svgNumber(p.X) + "," + svgNumber(p.Y)
+	}
+	return strings.Join(coords, " ")
+}
+
+// svgNumber formats a coordinate with at most one decimal
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+// svgEscape escapes text for use in SVG content and attribute values
+func svgEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(text)
+}
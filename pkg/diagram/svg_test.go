@@ -0,0 +1,66 @@
+package diagram
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImageSVG(t *testing.T) {
+	img := ActivityImage(JourneyStepsActivity(testJourney()))
+	svg := img.SVG()
+
+	// The document is well-formed XML despite the operators in the labels
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, svg)
+		}
+	}
+
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="` + svgNumber(img.Width) + `"`,
+		`<title>Journey Steps - from_9054_to_9012 - Rejected</title>`,
+		`>Step 2: appraising.cif</text>`,
+		`>if telco_code equals viettel</text>`,
+		`>rejected</text>`,
+		`fill="#ff9800" stroke="#ff9800"`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG is missing %s", want)
+		}
+	}
+
+	flow := GraphImage(&Graph{
+		Nodes: []Node{{ID: "a", Label: []string{`Config <1> & "2"`}}, {ID: "b", Label: []string{"Config 3"}}},
+		Edges: []Edge{{From: "a", To: "b", Undirected: true}},
+	}).SVG()
+	if !strings.Contains(flow, `>Config &lt;1&gt; &amp; &quot;2&quot;</text>`) || !strings.Contains(flow, `stroke-dasharray="6 4"`) {
+		t.Errorf("expected escaped text and a dashed link:\n%s", flow)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "images", "flow.svg")
+	img := GraphImage(JourneyFlowGraph(testTemplate()))
+	if err := img.WriteSVG(filename); err != nil {
+		t.Fatalf("WriteSVG: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil || string(data) != img.SVG() {
+		t.Errorf("expected the SVG to be written, got %v", err)
+	}
+}
+
+func TestSVGNumber(t *testing.T) {
+	for v, want := range map[float64]string{12: "12", 12.25: "12.3", -0.04: "-0", 3.14159: "3.1"} {
+		if got := svgNumber(v); got != want {
+			t.Errorf("svgNumber(%v) = %s, want %s", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+	"github.com/tsocial/ui-version-mapping/pkg/condition"
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+	"github.com/tsocial/ui-version-mapping/pkg/diagram"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+	"github.com/tsocial/ui-version-mapping/pkg/lint"
+)
+
+// Exporter writes the analysis artifacts (JSON, PlantUML, Mermaid, SVG, PNG, summary report)
+// produced by an AnalyzerService into an output Layout
+type Exporter struct {
+	service  *analyzer.AnalyzerService
+	layout   Layout
+	diagrams []diagram.Format
+	images   diagram.ImageBackend
+}
+
+// NewExporter creates an exporter writing under outputPath; diagrams are written as PlantUML
+// and rendered to PNG with PlantUML when available
+func NewExporter(service *analyzer.AnalyzerService, outputPath string) *Exporter {
+	return &Exporter{
+		service:  service,
+		layout:   NewLayout(outputPath),
+		diagrams: []diagram.Format{diagram.FormatPlantUML},
+		images:   diagram.ImageBackendAuto,
+	}
+}
+
+// SetDiagramFormats chooses the formats diagrams are written in
+func (e *Exporter) SetDiagramFormats(formats []diagram.Format) {
+	e.diagrams = formats
+}
+
+// SetImageBackend chooses how diagrams are rendered to PNG. The built-in renderer is used
+// whenever PlantUML is not: without a PlantUML source, or in auto mode without Java.
+func (e *Exporter) SetImageBackend(backend diagram.ImageBackend) {
+	e.images = backend
+}
+
+// DiagramFormats returns the formats diagrams are written in
+func (e *Exporter) DiagramFormats() []diagram.Format {
+	return e.diagrams
+}
+
+// Layout returns the output layout used by the exporter
+func (e *Exporter) Layout() Layout {
+	return e.layout
+}
+
+// ExportABTestingAnalysis runs the A/B testing analysis and writes its JSON and diagrams
+func (e *Exporter) ExportABTestingAnalysis(ctx context.Context, configID int, leadSource, folderPath string) (*analyzer.ABTestingAnalysisResult, error) {
+	result, err := e.service.AnalyzeABTesting(ctx, configID, leadSource, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze A/B testing: %w", err)
+	}
+
+	filename := e.layout.ABTestingJSON(configID, leadSource)
+	if err := writeJSON(result, filename); err != nil {
+		return nil, fmt.Errorf("failed to write A/B testing analysis: %w", err)
+	}
+	fmt.Printf("A/B testing analysis written to %s\n", filename)
+
+	// Generate diagrams if there are A/B testing groups
+	if len(result.ABTestingGroups) > 0 {
+		graph := diagram.ABTestingGraph(result.ABTestingGroups)
+		files := diagramFiles{
+			sources: map[diagram.Format]string{
+				diagram.FormatPlantUML: e.layout.ABTestingPuml(configID, leadSource),
+				diagram.FormatMermaid:  e.layout.ABTestingMermaid(configID, leadSource),
+			},
+			svg: e.layout.ABTestingSVG(configID, leadSource),
+			png: e.layout.ABTestingPNG(configID, leadSource),
+		}
+		err := e.exportDiagram("A/B testing", files, diagram.GraphImage(graph), func(r diagram.Renderer, filename string) error {
+			return diagram.WriteGraph(graph, r, filename)
+		})
+		if err != nil {
+			fmt.Printf("Warning: Failed to generate A/B testing diagram: %v\n", err)
+		}
+	}
+
+	return result, nil
+}
+
+// ExportABHealth runs the A/B traffic weight health check and writes its findings as JSON
+func (e *Exporter) ExportABHealth(ctx context.Context, folderPath string, expectedTotal int) (*analyzer.ABHealthReport, error) {
+	report, err := e.service.CheckABHealth(ctx, folderPath, expectedTotal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check A/B health: %w", err)
+	}
+
+	filename := e.layout.ABHealthJSON()
+	if err := writeJSON(report, filename); err != nil {
+		return nil, fmt.Errorf("failed to write A/B health report: %w", err)
+	}
+	fmt.Printf("A/B health report written to %s\n", filename)
+
+	return report, nil
+}
+
+// ExportLint lints the configs of a folder and writes the findings as JSON
+func (e *Exporter) ExportLint(ctx context.Context, folderPath string, linter *lint.Linter) (*lint.Report, error) {
+	report, err := e.service.LintConfigs(ctx, folderPath, linter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lint configs: %w", err)
+	}
+
+	filename := e.layout.LintJSON()
+	if err := writeJSON(report, filename); err != nil {
+		return nil, fmt.Errorf("failed to write lint report: %w", err)
+	}
+	fmt.Printf("Lint report written to %s\n", filename)
+
+	return report, nil
+}
+
+// ExportTopology builds the routing graph of the configs of a folder and writes it as JSON
+// and as a Graphviz DOT diagram clustered by clusterBy
+func (e *Exporter) ExportTopology(ctx context.Context, folderPath, clusterBy string) (*analyzer.ConfigTopology, error) {
+	topology, err := e.service.ConfigTopology(ctx, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config topology: %w", err)
+	}
+
+	filename := e.layout.TopologyJSON()
+	if err := writeJSON(topology, filename); err != nil {
+		return nil, fmt.Errorf("failed to write config topology: %w", err)
+	}
+	fmt.Printf("Config topology written to %s\n", filename)
+
+	dotFilename := e.layout.TopologyDOT()
+	if err := diagram.WriteDOT(diagram.TopologyGraph(topology, clusterBy), dotFilename); err != nil {
+		return nil, fmt.Errorf("failed to write config topology diagram: %w", err)
+	}
+	fmt.Printf("Config topology DOT diagram written to %s\n", dotFilename)
+
+	return topology, nil
+}
+
+// ExportTrafficSplit simulates the traffic split of the A/B groups containing a config and writes it as JSON
+func (e *Exporter) ExportTrafficSplit(ctx context.Context, configID int, folderPath string, opts analyzer.TrafficSplitOptions) ([]*analyzer.TrafficSplitResult, error) {
+	results, err := e.service.SimulateTrafficSplit(ctx, configID, folderPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate traffic split: %w", err)
+	}
+
+	filename := e.layout.TrafficSplitJSON(configID)
+	if err := writeJSON(results, filename); err != nil {
+		return nil, fmt.Errorf("failed to write traffic split simulation: %w", err)
+	}
+	fmt.Printf("Traffic split simulation written to %s\n", filename)
+
+	return results, nil
+}
+
+// ExportConfigDiff diffs the configs of base against the service's provider and writes the diff as JSON
+func (e *Exporter) ExportConfigDiff(ctx context.Context, base config.ConfigProvider, folderPath, baseLabel, headLabel string) (*analyzer.ConfigSnapshotDiff, error) {
+	diff, err := e.service.DiffConfigs(ctx, base, folderPath, baseLabel, headLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff configs: %w", err)
+	}
+
+	filename := e.layout.ConfigDiffJSON()
+	if err := writeJSON(diff, filename); err != nil {
+		return nil, fmt.Errorf("failed to write config diff: %w", err)
+	}
+	fmt.Printf("Config diff written to %s\n", filename)
+
+	return diff, nil
+}
+
+// ExportConfigHistory writes the change timeline of a config as JSON
+func (e *Exporter) ExportConfigHistory(ctx context.Context, configID int, leadSource string) (*analyzer.ConfigHistory, error) {
+	history, err := e.service.ConfigHistory(ctx, configID, leadSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config history: %w", err)
+	}
+
+	filename := e.layout.HistoryJSON(configID, leadSource)
+	if err := writeJSON(history, filename); err != nil {
+		return nil, fmt.Errorf("failed to write config history: %w", err)
+	}
+	fmt.Printf("Config history written to %s\n", filename)
+
+	return history, nil
+}
+
+// ExportConfigBlame writes the commit behind every ui_flow step of a config as JSON
+func (e *Exporter) ExportConfigBlame(ctx context.Context, configID int, leadSource string) (*analyzer.ConfigBlame, error) {
+	blame, err := e.service.ConfigBlame(ctx, configID, leadSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config blame: %w", err)
+	}
+
+	filename := e.layout.BlameJSON(configID, leadSource)
+	if err := writeJSON(blame, filename); err != nil {
+		return nil, fmt.Errorf("failed to write config blame: %w", err)
+	}
+	fmt.Printf("Config blame written to %s\n", filename)
+
+	return blame, nil
+}
+
+// ExportJourneyAnalysis generates the journey template and writes its JSON, flow diagram
+// and per-journey step diagrams
+func (e *Exporter) ExportJourneyAnalysis(ctx context.Context, configID int, leadSource, folderPath string) (*journey.JourneyTemplate, error) {
+	relatedConfigs, err := e.service.SearchRelatedConfigs(ctx, configID, leadSource, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find related configs: %w", err)
+	}
+	fmt.Printf("Found %d related configs\n", len(relatedConfigs))
+
+	template, err := e.service.GenerateJourneyTemplate(ctx, configID, leadSource, relatedConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate journey template: %w", err)
+	}
+
+	fmt.Printf("Generated %d journeys:\n", len(template.Journeys))
+	for i, j := range template.Journeys {
+		fmt.Printf("  %d. %s: %s -> %s (%d steps)\n", i+1, j.ID, j.FlowType, j.Description, len(j.Steps))
+	}
+
+	filename := e.layout.JourneyJSON(configID, leadSource)
+	if err := writeJSON(template, filename); err != nil {
+		return nil, fmt.Errorf("failed to write journey template: %w", err)
+	}
+	fmt.Printf("Journey template written to %s\n", filename)
+
+	// Generate journey flow diagram
+	flow := diagram.JourneyFlowGraph(template)
+	files := diagramFiles{
+		sources: map[diagram.Format]string{
+			diagram.FormatPlantUML: e.layout.JourneyFlowPuml(configID, leadSource),
+			diagram.FormatMermaid:  e.layout.JourneyFlowMermaid(configID, leadSource),
+		},
+		svg: e.layout.JourneyFlowSVG(configID, leadSource),
+		png: e.layout.JourneyFlowPNG(configID, leadSource),
+	}
+	err = e.exportDiagram("Journey flow", files, diagram.GraphImage(flow), func(r diagram.Renderer, filename string) error {
+		return diagram.WriteGraph(flow, r, filename)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate journey flow diagram: %w", err)
+	}
+
+	// Export individual journey step diagrams
+	for _, j := range template.Journeys {
+		steps := diagram.JourneyStepsActivity(j)
+		files := diagramFiles{
+			sources: map[diagram.Format]string{
+				diagram.FormatPlantUML: e.layout.JourneyStepsPuml(configID, leadSource, j.ID),
+				diagram.FormatMermaid:  e.layout.JourneyStepsMermaid(configID, leadSource, j.ID),
+			},
+			svg: e.layout.JourneyStepsSVG(configID, leadSource, j.ID),
+			png: e.layout.JourneyStepsPNG(configID, leadSource, j.ID),
+		}
+		err := e.exportDiagram("Journey steps", files, diagram.ActivityImage(steps), func(r diagram.Renderer, filename string) error {
+			return diagram.WriteActivity(steps, r, filename)
+		})
+		if err != nil {
+			fmt.Printf("Warning: Failed to export journey %s: %v\n", j.ID, err)
+		}
+	}
+
+	return template, nil
+}
+
+// ExportSimulation simulates the journey taken for attrs and writes it as JSON
+func (e *Exporter) ExportSimulation(ctx context.Context, configID int, leadSource, folderPath string, attrs condition.Attributes) (*journey.Simulation, error) {
+	sim, err := e.service.SimulateJourney(ctx, configID, leadSource, folderPath, attrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate journey: %w", err)
+	}
+
+	filename := e.layout.SimulationJSON(configID, leadSource)
+	if err := writeJSON(sim, filename); err != nil {
+		return nil, fmt.Errorf("failed to write journey simulation: %w", err)
+	}
+	fmt.Printf("Journey simulation written to %s\n", filename)
+
+	return sim, nil
+}
+
+// diagramFiles are the paths a diagram is written to
+type diagramFiles struct {
+	sources map[diagram.Format]string
+	svg     string
+	png     string
+}
+
+// exportDiagram writes a diagram in every configured format to its source path, then
+// writes its built-in SVG and renders its PNG
+func (e *Exporter) exportDiagram(description string, files diagramFiles, img *diagram.Image, write func(r diagram.Renderer, filename string) error) error {
+	var pumlFilename string
+	for _, format := range e.diagrams {
+		filename := files.sources[format]
+		if err := write(diagram.NewRenderer(format), filename); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s diagram written to %s\n", description, format.Name(), filename)
+
+		if format == diagram.FormatPlantUML {
+			pumlFilename = filename
+		}
+	}
+
+	if err := img.WriteSVG(files.svg); err != nil {
+		return err
+	}
+	fmt.Printf("%s SVG diagram written to %s\n", description, files.svg)
+
+	e.exportPNG(description, pumlFilename, files.png, img)
+	return nil
+}
+
+// exportPNG renders a diagram to PNG with PlantUML when the image backend allows it and a
+// PlantUML source was written, and with the built-in renderer otherwise. In auto mode a
+// failing PlantUML run falls back to the built-in renderer; failures are reported as warnings.
+func (e *Exporter) exportPNG(description, pumlFilename, pngFilename string, img *diagram.Image) {
+	usePlantUML := pumlFilename != "" &&
+		(e.images == diagram.ImageBackendPlantUML || (e.images == diagram.ImageBackendAuto && diagram.PlantUMLAvailable()))
+	if usePlantUML {
+		err := diagram.ExportPlantUMLToPNG(pumlFilename, pngFilename)
+		if err == nil {
+			return
+		}
+		if e.images == diagram.ImageBackendPlantUML {
+			fmt.Printf("Warning: Failed to export PNG (Java/PlantUML may not be available): %v\n", err)
+			return
+		}
+		fmt.Printf("Warning: Failed to export PNG with PlantUML, using the built-in renderer: %v\n", err)
+	}
+
+	if err := img.WritePNG(pngFilename); err != nil {
+		fmt.Printf("Warning: Failed to export PNG: %v\n", err)
+		return
+	}
+	fmt.Printf("%s PNG diagram written to %s\n", description, pngFilename)
+}
+
+// writeJSON marshals v with indentation and writes it to filename
+func writeJSON(v interface{}, filename string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to prepare file path: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if err := os.WriteFile(filename, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON file %s: %w", filename, err)
+	}
+
+	return nil
+}
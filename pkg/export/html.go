@@ -0,0 +1,369 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+	"github.com/tsocial/ui-version-mapping/pkg/condition"
+	"github.com/tsocial/ui-version-mapping/pkg/diagram"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+)
+
+// GenerateHTMLReport creates a self-contained HTML report of the analyses written for a
+// config: related configs with their match reasons, A/B groups with flow diffs, the journeys
+// with their steps and UI versions, and the diagrams inlined as SVG. The file opens offline.
+func (e *Exporter) GenerateHTMLReport(configID int, leadSource string) error {
+	var abAnalysis *analyzer.ABTestingAnalysisResult
+	if data, err := os.ReadFile(e.layout.ABTestingJSON(configID, leadSource)); err == nil {
+		var result analyzer.ABTestingAnalysisResult
+		if json.Unmarshal(data, &result) == nil {
+			abAnalysis = &result
+		}
+	}
+
+	var journeyTemplate *journey.JourneyTemplate
+	if data, err := os.ReadFile(e.layout.JourneyJSON(configID, leadSource)); err == nil {
+		var result journey.JourneyTemplate
+		if json.Unmarshal(data, &result) == nil {
+			journeyTemplate = &result
+			// Decision steps are not part of the template JSON
+			for i := range journeyTemplate.Journeys {
+				e.service.StepCatalogue().Annotate(journeyTemplate.Journeys[i].Steps)
+			}
+		}
+	}
+
+	var warnings []string
+	for _, d := range e.service.LoadDiagnostics() {
+		warnings = append(warnings, fmt.Sprintf("Config file skipped: %s", d))
+	}
+	for _, d := range e.service.LoadDuplicates() {
+		warnings = append(warnings, d.String())
+	}
+
+	report := buildHTMLReport(configID, leadSource, abAnalysis, journeyTemplate, warnings)
+	report.Generated = time.Now().Format("2006-01-02 15:04:05")
+
+	filename := e.layout.HTMLReport(configID, leadSource)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to prepare HTML report path: %w", err)
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create HTML report %s: %w", filename, err)
+	}
+	if err := renderHTMLReport(file, report); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write HTML report %s: %w", filename, err)
+	}
+
+	fmt.Printf("HTML report written to %s\n", filename)
+	return nil
+}
+
+// htmlReport is the data of the HTML report
+type htmlReport struct {
+	ConfigID   int
+	LeadSource string
+	Generated  string
+	Related    []htmlConfig
+	Groups     []htmlGroup
+	Journeys   []htmlJourney
+	FlowSVG    template.HTML
+	GroupsSVG  template.HTML
+	Warnings   []string
+	// anchors are the configs with a section or row in the report, by config ID
+	anchors map[int]bool
+}
+
+// htmlConfig is a row of the related configs table
+type htmlConfig struct {
+	ConfigID    int
+	Name        string
+	FlowType    string
+	UIVersion   string
+	Weight      int
+	MatchReason string
+	MatchedTags string
+	Journeys    []string
+}
+
+type htmlGroup struct {
+	Name        string
+	Strategy    string
+	TotalWeight int
+	Variants    []htmlVariant
+}
+
+type htmlVariant struct {
+	ConfigID int
+	Name     string
+	Weight   int
+	Share    string
+	Steps    int
+	Summary  string
+	Diff     []string
+	// Anchor is set on the first appearance of a config not in the related configs table
+	Anchor bool
+}
+
+type htmlJourney struct {
+	ID          string
+	FlowType    string
+	Description string
+	Condition   string
+	From        int
+	To          int
+	Steps       []htmlStep
+	SVG         template.HTML
+}
+
+type htmlStep struct {
+	ID         int
+	Name       string
+	Decision   bool
+	Main       string
+	Sub        string
+	Resolved   string
+	Conditions []string
+}
+
+// buildHTMLReport gathers the report data; nil analyses are left out
+func buildHTMLReport(configID int, leadSource string, abAnalysis *analyzer.ABTestingAnalysisResult, journeyTemplate *journey.JourneyTemplate, warnings []string) *htmlReport {
+	report := &htmlReport{
+		ConfigID:   configID,
+		LeadSource: leadSource,
+		Warnings:   warnings,
+		anchors:    map[int]bool{configID: true},
+	}
+
+	journeysTo := make(map[int][]string)
+	if journeyTemplate != nil {
+		for _, j := range journeyTemplate.Journeys {
+			journeysTo[j.ToLenderConfigID] = append(journeysTo[j.ToLenderConfigID], j.ID)
+		}
+	}
+
+	if abAnalysis != nil {
+		for _, result := range abAnalysis.NormalResults {
+			var tags []string
+			for _, tag := range result.MatchedTags {
+				tags = append(tags, fmt.Sprintf("%s=%s", tag.Name, tag.Value))
+			}
+			report.Related = append(report.Related, htmlConfig{
+				ConfigID:    result.ConfigID,
+				Name:        result.Name,
+				FlowType:    result.FlowType,
+				UIVersion:   result.UIVersion,
+				Weight:      result.Weight,
+				MatchReason: result.MatchReason,
+				MatchedTags: strings.Join(tags, ", "),
+				Journeys:    journeysTo[result.ConfigID],
+			})
+			report.anchors[result.ConfigID] = true
+		}
+
+		for _, group := range abAnalysis.ABTestingGroups {
+			g := htmlGroup{Name: group.GroupName, Strategy: group.Strategy, TotalWeight: group.TotalWeight}
+			for _, variant := range group.Variants {
+				v := htmlVariant{
+					ConfigID: variant.ConfigID,
+					Name:     variant.Name,
+					Weight:   variant.Weight,
+					Steps:    len(variant.UIFlow),
+					Diff:     analyzer.FlowDiffStrings(variant.FlowDiff),
+					Anchor:   !report.anchors[variant.ConfigID],
+				}
+				if group.TotalWeight > 0 {
+					v.Share = fmt.Sprintf("%.1f%%", float64(variant.Weight)/float64(group.TotalWeight)*100)
+				}
+				if len(variant.FlowDiff) > 0 {
+					v.Summary = analyzer.FlowDiffSummary(variant.FlowDiff)
+				}
+				report.anchors[variant.ConfigID] = true
+				g.Variants = append(g.Variants, v)
+			}
+			report.Groups = append(report.Groups, g)
+		}
+		if len(abAnalysis.ABTestingGroups) > 0 {
+			report.GroupsSVG = template.HTML(diagram.GraphImage(diagram.ABTestingGraph(abAnalysis.ABTestingGroups)).SVG())
+		}
+	}
+
+	if journeyTemplate != nil {
+		report.FlowSVG = template.HTML(diagram.GraphImage(diagram.JourneyFlowGraph(journeyTemplate)).SVG())
+		for _, j := range journeyTemplate.Journeys {
+			item := htmlJourney{
+				ID:          j.ID,
+				FlowType:    j.FlowType,
+				Description: j.Description,
+				Condition:   j.Condition,
+				From:        j.FromLenderConfigID,
+				To:          j.ToLenderConfigID,
+				SVG:         template.HTML(diagram.ActivityImage(diagram.JourneyStepsActivity(j)).SVG()),
+			}
+			for _, step := range j.Steps {
+				s := htmlStep{
+					ID:       step.ID,
+					Name:     step.Name,
+					Decision: step.Decision,
+					Main:     step.MainUIVersion,
+					Sub:      step.SubUIVersion,
+					// The UI version shown when no condition matches
+					Resolved: journey.ResolvedStep{MainUIVersion: step.MainUIVersion, SubUIVersion: step.SubUIVersion}.UIVersion(),
+				}
+				for _, c := range step.SubUIVersionByConditions {
+					s.Conditions = append(s.Conditions, fmt.Sprintf("%s → %s", condition.DescribeString(c.Condition), c.SubUIVersion))
+				}
+				item.Steps = append(item.Steps, s)
+			}
+			report.Journeys = append(report.Journeys, item)
+		}
+	}
+
+	return report
+}
+
+// renderHTMLReport writes the report as one HTML page with inline style and script
+func renderHTMLReport(w io.Writer, report *htmlReport) error {
+	page, err := template.New("report").Funcs(template.FuncMap{
+		// config links to the row or section of a config when the report has one
+		"config": func(configID int) template.HTML {
+			label := template.HTMLEscapeString(fmt.Sprintf("Config %d", configID))
+			if !report.anchors[configID] {
+				return template.HTML(label)
+			}
+			return template.HTML(fmt.Sprintf(`<a href="#config-%d">%s</a>`, configID, label))
+		},
+		"inc": func(i int) int { return i + 1 },
+	}).Parse(htmlReportTemplate)
+	if err != nil {
+		return err
+	}
+	return page.Execute(w, report)
+}
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Analysis Report - Config {{.ConfigID}} ({{.LeadSource}})</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1200px; padding: 0 1em; color: #222; }
+  h1, h2 { color: #2196F3; }
+  table { border-collapse: collapse; width: 100%; margin: 0.5em 0 1em; }
+  th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+  th { background: #f5f5f5; }
+  tr:target, section:target > h3 { background: #fff9c4; }
+  details { border: 1px solid #ddd; border-radius: 4px; margin: 0.5em 0; padding: 0.5em 1em; }
+  summary { cursor: pointer; font-weight: bold; }
+  .diagram { overflow-x: auto; }
+  .diagram svg { max-width: none; }
+  .muted { color: #777; }
+  .decision { color: #e51c23; font-weight: bold; }
+  .warning { color: #ff9800; }
+  #search { width: 100%; padding: 6px; margin: 0.5em 0; box-sizing: border-box; }
+</style>
+</head>
+<body>
+<h1 id="config-{{.ConfigID}}">Analysis Report - Config {{.ConfigID}}</h1>
+<p><strong>Lead Source:</strong> {{.LeadSource}}{{if .Generated}} · <strong>Generated:</strong> {{.Generated}}{{end}}</p>
+{{- if .Warnings}}
+<h2>Load Warnings</h2>
+<ul>
+{{- range .Warnings}}
+  <li class="warning">{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+
+<h2>Related Configs</h2>
+{{- if .Related}}
+<input id="search" type="search" placeholder="Filter by ID, name, flow type, UI version or match reason">
+<table id="related">
+  <thead><tr><th>Config</th><th>Name</th><th>Flow Type</th><th>UI Version</th><th>Weight</th><th>Match Reason</th><th>Matched Tags</th><th>Journeys</th></tr></thead>
+  <tbody>
+{{- range .Related}}
+    <tr id="config-{{.ConfigID}}"><td>{{.ConfigID}}</td><td>{{.Name}}</td><td>{{.FlowType}}</td><td>{{.UIVersion}}</td><td>{{.Weight}}</td><td>{{.MatchReason}}</td><td>{{.MatchedTags}}</td><td>{{range $i, $id := .Journeys}}{{if $i}}, {{end}}<a href="#journey-{{$id}}">{{$id}}</a>{{end}}</td></tr>
+{{- end}}
+  </tbody>
+</table>
+{{- else}}
+<p class="muted">No related configs.</p>
+{{- end}}
+
+<h2>A/B Testing Groups</h2>
+{{- range $i, $group := .Groups}}
+<details>
+  <summary>Group {{inc $i}}: {{$group.Name}} ({{len $group.Variants}} variants, total weight {{$group.TotalWeight}})</summary>
+  {{- if $group.Strategy}}
+  <p class="muted">Grouped by: {{$group.Strategy}}</p>
+  {{- end}}
+  <table>
+    <thead><tr><th>Config</th><th>Name</th><th>Weight</th><th>Share</th><th>Steps</th><th>Flow Diff vs Original</th></tr></thead>
+    <tbody>
+    {{- range $group.Variants}}
+      <tr{{if .Anchor}} id="config-{{.ConfigID}}"{{end}}><td>{{config .ConfigID}}</td><td>{{.Name}}</td><td>{{.Weight}}</td><td>{{.Share}}</td><td>{{.Steps}}</td><td>{{if .Diff}}{{.Summary}}<ul>{{range .Diff}}<li>{{.}}</li>{{end}}</ul>{{else}}<span class="muted">same flow</span>{{end}}</td></tr>
+    {{- end}}
+    </tbody>
+  </table>
+</details>
+{{- else}}
+<p class="muted">No A/B testing groups.</p>
+{{- end}}
+{{- if .GroupsSVG}}
+<details>
+  <summary>A/B testing diagram</summary>
+  <div class="diagram">{{.GroupsSVG}}</div>
+</details>
+{{- end}}
+
+<h2>Journeys</h2>
+{{- if .FlowSVG}}
+<div class="diagram">{{.FlowSVG}}</div>
+{{- end}}
+{{- range .Journeys}}
+<section id="journey-{{.ID}}">
+<h3>{{.ID}}: {{config .From}} → {{config .To}}</h3>
+<p><strong>Flow Type:</strong> {{.FlowType}}{{if .Description}} · {{.Description}}{{end}}{{if .Condition}} · <strong>Condition:</strong> <code>{{.Condition}}</code>{{end}}</p>
+<table>
+  <thead><tr><th>#</th><th>Step</th><th>Main UI</th><th>Sub UI</th><th>Resolved UI Version</th><th>Conditional UI Versions</th></tr></thead>
+  <tbody>
+  {{- range .Steps}}
+    <tr><td>{{.ID}}</td><td>{{.Name}}{{if .Decision}} <span class="decision" title="Rejection ends the journey">decision</span>{{end}}</td><td>{{.Main}}</td><td>{{.Sub}}</td><td>{{.Resolved}}</td><td>{{range .Conditions}}{{.}}<br>{{end}}</td></tr>
+  {{- end}}
+  </tbody>
+</table>
+<details>
+  <summary>Step diagram</summary>
+  <div class="diagram">{{.SVG}}</div>
+</details>
+</section>
+{{- else}}
+<p class="muted">No journeys.</p>
+{{- end}}
+
+<script>
+  var search = document.getElementById("search");
+  if (search) {
+    search.addEventListener("input", function () {
+      var query = search.value.toLowerCase();
+      document.querySelectorAll("#related tbody tr").forEach(function (row) {
+        row.style.display = row.textContent.toLowerCase().indexOf(query) >= 0 ? "" : "none";
+      });
+    });
+  }
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+)
+
+func TestRenderHTMLReport(t *testing.T) {
+	abAnalysis := &analyzer.ABTestingAnalysisResult{
+		NormalResults: []config.RelatedConfigResult{{
+			ConfigID:    9012,
+			Name:        "evo <semi>",
+			FlowType:    "semi",
+			UIVersion:   "v9.1.4.0",
+			Weight:      100,
+			MatchReason: "same lead_source, different flow_type: semi",
+			MatchedTags: []config.Tag{{Name: "lead_source", Value: "organic"}},
+		}},
+		ABTestingGroups: []analyzer.ABTestingGroup{{
+			GroupName:   "evo.auto",
+			TotalWeight: 100,
+			Variants: []analyzer.ABTestingVariant{
+				{ConfigID: 9054, Name: "evo.auto", Weight: 70, UIFlow: []string{"otp", "esign.review"}},
+				{ConfigID: 9055, Name: "evo.auto", Weight: 30, UIFlow: []string{"otp"},
+					FlowDiff: analyzer.DiffUIFlows([]string{"otp", "esign.review"}, []string{"otp"})},
+			},
+		}},
+	}
+	template := &journey.JourneyTemplate{
+		SearchValue: 9054,
+		Journeys: []journey.Journey{{
+			ID:                 "from_9054_to_9012",
+			FlowType:           "auto_to_semi",
+			FromLenderConfigID: 9054,
+			ToLenderConfigID:   9012,
+			Steps: []journey.Step{
+				{ID: 0, Name: "otp", MainUIVersion: "v1", SubUIVersion: "v1.0-auto", SubUIVersionByConditions: []journey.SubUIVersionByCondition{
+					{Condition: "telco_code=viettel", SubUIVersion: "v1.1-auto"},
+				}},
+				{ID: 1, Name: "appraising.cif", MainUIVersion: "v2", Decision: true},
+			},
+		}},
+	}
+
+	var page strings.Builder
+	report := buildHTMLReport(9054, "organic", abAnalysis, template, []string{"config 1 is declared twice"})
+	if err := renderHTMLReport(&page, report); err != nil {
+		t.Fatalf("renderHTMLReport: %v", err)
+	}
+	html := page.String()
+
+	for _, want := range []string{
+		`<h1 id="config-9054">Analysis Report - Config 9054</h1>`,
+		`<li class="warning">config 1 is declared twice</li>`,
+		// Related configs are escaped, searchable and link to their journeys
+		`<input id="search" type="search"`,
+		`<tr id="config-9012"><td>9012</td><td>evo &lt;semi&gt;</td>`,
+		`<td>lead_source=organic</td><td><a href="#journey-from_9054_to_9012">from_9054_to_9012</a></td>`,
+		// A/B groups are collapsible; configs without a row get one in their group
+		`<summary>Group 1: evo.auto (2 variants, total weight 100)</summary>`,
+		`<td><a href="#config-9054">Config 9054</a></td><td>evo.auto</td><td>70</td><td>70.0%</td>`,
+		`<tr id="config-9055"><td><a href="#config-9055">Config 9055</a></td>`,
+		`<li>Step 2: esign.review (missing in variant)</li>`,
+		// Journeys link both configs and resolve the UI version of each step
+		`<section id="journey-from_9054_to_9012">`,
+		`<h3>from_9054_to_9012: <a href="#config-9054">Config 9054</a> → <a href="#config-9012">Config 9012</a></h3>`,
+		`<td>v1</td><td>v1.0-auto</td><td>v1/v1.0-auto</td><td>telco_code equals viettel → v1.1-auto<br></td>`,
+		`appraising.cif <span class="decision"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML report is missing %s", want)
+		}
+	}
+
+	// The flow, A/B and step diagrams are inlined
+	if count := strings.Count(html, "<svg "); count != 3 {
+		t.Errorf("expected 3 inline SVG diagrams, got %d", count)
+	}
+	if strings.Contains(html, "<script src") || strings.Contains(html, `<link `) {
+		t.Error("expected a self-contained page")
+	}
+}
+
+func TestRenderHTMLReportEmpty(t *testing.T) {
+	var page strings.Builder
+	if err := renderHTMLReport(&page, buildHTMLReport(9054, "organic", nil, nil, nil)); err != nil {
+		t.Fatalf("renderHTMLReport: %v", err)
+	}
+	for _, want := range []string{"No related configs.", "No A/B testing groups.", "No journeys."} {
+		if !strings.Contains(page.String(), want) {
+			t.Errorf("expected %q", want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Output directory structure for a lender config
+const (
+	PumlDir    = "pumls"
+	MermaidDir = "mermaid"
+	ImagesDir  = "images"
+)
+
+// Layout resolves where analysis artifacts are written under an output base directory
+type Layout struct {
+	BaseDir string
+}
+
+// NewLayout creates a layout rooted at baseDir
+func NewLayout(baseDir string) Layout {
+	return Layout{BaseDir: baseDir}
+}
+
+// ResultsDir returns the directory holding JSON and report files for a config
+func (l Layout) ResultsDir(configID int) string {
+	return filepath.Join(l.BaseDir, fmt.Sprintf("%d", configID))
+}
+
+// PumlDir returns the directory holding PlantUML sources for a config
+func (l Layout) PumlDir(configID int) string {
+	return filepath.Join(l.ResultsDir(configID), PumlDir)
+}
+
+// MermaidDir returns the directory holding Mermaid sources for a config
+func (l Layout) MermaidDir(configID int) string {
+	return filepath.Join(l.ResultsDir(configID), MermaidDir)
+}
+
+// ImagesDir returns the directory holding rendered images for a config
+func (l Layout) ImagesDir(configID int) string {
+	return filepath.Join(l.ResultsDir(configID), ImagesDir)
+}
+
+// ABTestingJSON returns the A/B testing analysis JSON path
+func (l Layout) ABTestingJSON(configID int, leadSource string) string {
+	return filepath.Join(l.ResultsDir(configID), fmt.Sprintf("ab_testing_analysis_%d_%s.json", configID, leadSource))
+}
+
+// ABTestingPuml returns the A/B testing groups PlantUML path
+func (l Layout) ABTestingPuml(configID int, leadSource string) string {
+	return filepath.Join(l.PumlDir(configID), fmt.Sprintf("ab_testing_groups_%d_%s.puml", configID, leadSource))
+}
+
+// ABTestingMermaid returns the A/B testing groups Mermaid path
+func (l Layout) ABTestingMermaid(configID int, leadSource string) string {
+	return filepath.Join(l.MermaidDir(configID), fmt.Sprintf("ab_testing_groups_%d_%s.mmd", configID, leadSource))
+}
+
+// ABTestingPNG returns the A/B testing groups PNG path
+func (l Layout) ABTestingPNG(configID int, leadSource string) string {
+	return filepath.Join(l.ImagesDir(configID), fmt.Sprintf("ab_testing_groups_%d_%s.png", configID, leadSource))
+}
+
+// ABTestingSVG returns the A/B testing groups SVG path
+func (l Layout) ABTestingSVG(configID int, leadSource string) string {
+	return filepath.Join(l.ImagesDir(configID), fmt.Sprintf("ab_testing_groups_%d_%s.svg", configID, leadSource))
+}
+
+// JourneyJSON returns the journey analysis JSON path
+func (l Layout) JourneyJSON(configID int, leadSource string) string {
+	return filepath.Join(l.ResultsDir(configID), fmt.Sprintf("journey_analysis_%d_%s.json", configID, leadSource))
+}
+
+// JourneyFlowPuml returns the journey flow PlantUML path
+func (l Layout) JourneyFlowPuml(configID int, leadSource string) string {
+	return filepath.Join(l.PumlDir(configID), fmt.Sprintf("journey_flow_%d_%s.puml", configID, leadSource))
+}
+
+// JourneyFlowMermaid returns the journey flow Mermaid path
+func (l Layout) JourneyFlowMermaid(configID int, leadSource string) string {
+	return filepath.Join(l.MermaidDir(configID), fmt.Sprintf("journey_flow_%d_%s.mmd", configID, leadSource))
+}
+
+// JourneyFlowPNG returns the journey flow PNG path
+func (l Layout) JourneyFlowPNG(configID int, leadSource string) string {
+	return filepath.Join(l.ImagesDir(configID), fmt.Sprintf("journey_flow_%d_%s.png", configID, leadSource))
+}
+
+// JourneyFlowSVG returns the journey flow SVG path
+func (l Layout) JourneyFlowSVG(configID int, leadSource string) string {
+	return filepath.Join(l.ImagesDir(configID), fmt.Sprintf("journey_flow_%d_%s.svg", configID, leadSource))
+}
+
+// JourneyStepsPuml returns the PlantUML path of an individual journey
+func (l Layout) JourneyStepsPuml(configID int, leadSource, journeyID string) string {
+	return filepath.Join(l.PumlDir(configID), fmt.Sprintf("journey_steps_%d_%s_%s.puml", configID, leadSource, sanitizeFilename(journeyID)))
+}
+
+// JourneyStepsMermaid returns the Mermaid path of an individual journey
+func (l Layout) JourneyStepsMermaid(configID int, leadSource, journeyID string) string {
+	return filepath.Join(l.MermaidDir(configID), fmt.Sprintf("journey_steps_%d_%s_%s.mmd", configID, leadSource, sanitizeFilename(journeyID)))
+}
+
+// JourneyStepsPNG returns the PNG path of an individual journey
+func (l Layout) JourneyStepsPNG(configID int, leadSource, journeyID string) string {
+	return filepath.Join(l.ImagesDir(configID), fmt.Sprintf("journey_steps_%d_%s_%s.png", configID, leadSource, sanitizeFilename(journeyID)))
+}
+
+// JourneyStepsSVG returns the SVG path of an individual journey
+func (l Layout) JourneyStepsSVG(configID int, leadSource, journeyID string) string {
+	return filepath.Join(l.ImagesDir(configID), fmt.Sprintf("journey_steps_%d_%s_%s.svg", configID, leadSource, sanitizeFilename(journeyID)))
+}
+
+// SimulationJSON returns the journey simulation JSON path
+func (l Layout) SimulationJSON(configID int, leadSource string) string {
+	return filepath.Join(l.ResultsDir(configID), fmt.Sprintf("journey_simulation_%d_%s.json", configID, leadSource))
+}
+
+// ABHealthJSON returns the A/B health report path; the check covers a whole config folder
+func (l Layout) ABHealthJSON() string {
+	return filepath.Join(l.BaseDir, "ab_health_report.json")
+}
+
+// LintJSON returns the lint report path; the lint covers a whole config folder
+func (l Layout) LintJSON() string {
+	return filepath.Join(l.BaseDir, "lint_report.json")
+}
+
+// TopologyJSON returns the config routing topology path; the topology covers a whole config folder
+func (l Layout) TopologyJSON() string {
+	return filepath.Join(l.BaseDir, "config_topology.json")
+}
+
+// TopologyDOT returns the Graphviz DOT path of the config routing topology
+func (l Layout) TopologyDOT() string {
+	return filepath.Join(l.BaseDir, "config_topology.dot")
+}
+
+// TrafficSplitJSON returns the traffic split simulation JSON path
+func (l Layout) TrafficSplitJSON(configID int) string {
+	return filepath.Join(l.ResultsDir(configID), fmt.Sprintf("traffic_split_%d.json", configID))
+}
+
+// ConfigDiffJSON returns the config snapshot diff path
+func (l Layout) ConfigDiffJSON() string {
+	return filepath.Join(l.BaseDir, "config_diff.json")
+}
+
+// HistoryJSON returns the config history JSON path
+func (l Layout) HistoryJSON(configID int, leadSource string) string {
+	return filepath.Join(l.ResultsDir(configID), fmt.Sprintf("config_history_%d_%s.json", configID, leadSource))
+}
+
+// BlameJSON returns the ui_flow blame JSON path
+func (l Layout) BlameJSON(configID int, leadSource string) string {
+	return filepath.Join(l.ResultsDir(configID), fmt.Sprintf("config_blame_%d_%s.json", configID, leadSource))
+}
+
+// UIVersionMappingCSV returns the CSV path of the step-to-UI-version mapping
+func (l Layout) UIVersionMappingCSV(configID int, leadSource string) string {
+	return filepath.Join(l.ResultsDir(configID), fmt.Sprintf("ui_version_mapping_%d_%s.csv", configID, leadSource))
+}
+
+// UIVersionMappingXLSX returns the XLSX path of the step-to-UI-version mapping
+func (l Layout) UIVersionMappingXLSX(configID int, leadSource string) string {
+	return filepath.Join(l.ResultsDir(configID), fmt.Sprintf("ui_version_mapping_%d_%s.xlsx", configID, leadSource))
+}
+
+// SummaryReport returns the Markdown summary report path
+func (l Layout) SummaryReport(configID int, leadSource string) string {
+	return filepath.Join(l.ResultsDir(configID), fmt.Sprintf("summary_report_%d_%s.md", configID, leadSource))
+}
+
+// HTMLReport returns the self-contained HTML report path
+func (l Layout) HTMLReport(configID int, leadSource string) string {
+	return filepath.Join(l.ResultsDir(configID), fmt.Sprintf("report_%d_%s.html", configID, leadSource))
+}
+
+// sanitizeFilename removes invalid characters from filename
+func sanitizeFilename(filename string) string {
+	replacer := strings.NewReplacer(
+		"/", "_", "\\", "_", ":", "_", "*", "_", "?", "_",
+		"\"", "_", "<", "_", ">", "_", "|", "_",
+	)
+	return replacer.Replace(filename)
+}
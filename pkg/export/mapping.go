@@ -0,0 +1,81 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+)
+
+// abGroupColumns are the columns of the A/B groups worksheet
+var abGroupColumns = []string{"Group", "Strategy", "LenderConfigID", "Name", "Weight", "Share", "UIFlow", "FlowDiff", "Differences"}
+
+// ExportUIVersionMapping flattens every journey step of a config into mapping rows and writes
+// them as CSV and as an XLSX workbook with one worksheet per target config and one for the
+// A/B testing groups
+func (e *Exporter) ExportUIVersionMapping(ctx context.Context, configID int, leadSource, folderPath string) (*analyzer.UIVersionMapping, error) {
+	mapping, err := e.service.UIVersionMapping(ctx, configID, leadSource, folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build UI version mapping: %w", err)
+	}
+
+	all := sheet{name: "UI Versions", header: analyzer.MappingColumns}
+	for _, row := range mapping.Rows {
+		all.rows = append(all.rows, row.Values())
+	}
+	filename := e.layout.UIVersionMappingCSV(configID, leadSource)
+	if err := writeCSV(all, filename); err != nil {
+		return nil, fmt.Errorf("failed to write UI version mapping CSV: %w", err)
+	}
+	fmt.Printf("UI version mapping CSV written to %s\n", filename)
+
+	filename = e.layout.UIVersionMappingXLSX(configID, leadSource)
+	if err := writeXLSX(mappingSheets(mapping), filename); err != nil {
+		return nil, fmt.Errorf("failed to write UI version mapping XLSX: %w", err)
+	}
+	fmt.Printf("UI version mapping XLSX written to %s\n", filename)
+
+	return mapping, nil
+}
+
+// mappingSheets returns the worksheets of the mapping workbook: the rows of each target
+// config, renumbered from 1 and in order of first appearance, then the A/B testing groups
+func mappingSheets(mapping *analyzer.UIVersionMapping) []sheet {
+	var sheets []sheet
+	index := make(map[string]int)
+	for _, row := range mapping.Rows {
+		i, ok := index[row.LenderConfigID]
+		if !ok {
+			i = len(sheets)
+			index[row.LenderConfigID] = i
+			sheets = append(sheets, sheet{name: "Config " + row.LenderConfigID, header: analyzer.MappingColumns})
+		}
+		row.STT = len(sheets[i].rows) + 1
+		sheets[i].rows = append(sheets[i].rows, row.Values())
+	}
+
+	// Sheet names cannot contain "/"
+	groups := sheet{name: "A-B Groups", header: abGroupColumns}
+	for _, group := range mapping.ABTestingGroups {
+		for _, variant := range group.Variants {
+			share := ""
+			if group.TotalWeight > 0 {
+				share = fmt.Sprintf("%.1f%%", float64(variant.Weight)/float64(group.TotalWeight)*100)
+			}
+			groups.rows = append(groups.rows, []string{
+				group.GroupName,
+				group.Strategy,
+				strconv.Itoa(variant.ConfigID),
+				variant.Name,
+				strconv.Itoa(variant.Weight),
+				share,
+				strings.Join(variant.UIFlow, ", "),
+				strings.Join(analyzer.FlowDiffStrings(variant.FlowDiff), "; "),
+				strings.Join(variant.Differences, "; "),
+			})
+		}
+	}
+	return append(sheets, groups)
+}
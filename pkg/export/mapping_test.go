@@ -0,0 +1,142 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+)
+
+func TestMappingSheets(t *testing.T) {
+	mapping := &analyzer.UIVersionMapping{
+		Rows: []analyzer.MappingRow{
+			{STT: 1, LenderConfigID: "9012", UIFlow: "cif.confirm"},
+			{STT: 2, LenderConfigID: "9013", UIFlow: "cif.confirm"},
+			{STT: 3, LenderConfigID: "9012", UIFlow: "appraising.cif"},
+		},
+		ABTestingGroups: []analyzer.ABTestingGroup{{
+			GroupName:   "evo.auto",
+			TotalWeight: 100,
+			Variants: []analyzer.ABTestingVariant{
+				{ConfigID: 9054, Name: "evo.auto", Weight: 70, UIFlow: []string{"otp", "esign.review"}},
+				{ConfigID: 9055, Name: "evo.auto", Weight: 30, UIFlow: []string{"otp"},
+					FlowDiff: analyzer.DiffUIFlows([]string{"otp", "esign.review"}, []string{"otp"})},
+			},
+		}},
+	}
+
+	sheets := mappingSheets(mapping)
+
+	var names []string
+	for _, s := range sheets {
+		names = append(names, s.name)
+	}
+	if want := []string{"Config 9012", "Config 9013", "A-B Groups"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected sheets %v, got %v", want, names)
+	}
+	// Rows are renumbered per config
+	if rows := sheets[0].rows; len(rows) != 2 || rows[1][0] != "2" || rows[1][4] != "appraising.cif" {
+		t.Errorf("unexpected config rows %v", rows)
+	}
+	want := []string{"evo.auto", "", "9055", "evo.auto", "30", "30.0%", "otp", "Step 2: esign.review (missing in variant)", ""}
+	if rows := sheets[2].rows; len(rows) != 2 || !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("unexpected A/B group rows %v", rows)
+	}
+}
+
+func TestEncodeXLSX(t *testing.T) {
+	sheets := []sheet{
+		{name: "Config 9012", header: []string{"STT", "UIFlow"}, rows: [][]string{{"1", "otp <&>"}, {"2", ""}}},
+		{name: "Config 9012", header: []string{"Name"}},
+		{name: "A/B Groups: [all]"},
+	}
+
+	var buf bytes.Buffer
+	if err := encodeXLSX(&buf, sheets); err != nil {
+		t.Fatalf("encodeXLSX: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("expected a zip archive: %v", err)
+	}
+
+	parts := make(map[string]string)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(r)
+		r.Close()
+		// Every part must be well-formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(data))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("part %s is not well-formed: %v", f.Name, err)
+			}
+		}
+		parts[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet3.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+	for _, want := range []string{`name="Config 9012"`, `name="Config 9012 (2)"`, `name="A_B Groups_ (all)"`} {
+		if !strings.Contains(parts["xl/workbook.xml"], want) {
+			t.Errorf("expected sheet %s in workbook:\n%s", want, parts["xl/workbook.xml"])
+		}
+	}
+
+	worksheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">STT</t></is></c>`,
+		`<c r="A2"><v>1</v></c>`,
+		`<c r="B2" t="inlineStr"><is><t xml:space="preserve">otp &lt;&amp;&gt;</t></is></c>`,
+		`<row r="3"><c r="A3"><v>2</v></c></row>`,
+		`<autoFilter ref="A1:B3"/>`,
+	} {
+		if !strings.Contains(worksheet, want) {
+			t.Errorf("expected %s in worksheet:\n%s", want, worksheet)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", index, got, want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "9054", "mapping.csv")
+	s := sheet{header: []string{"STT", "SubUIVersionState"}, rows: [][]string{{"1", "if telco_code=viettel, vinaphone"}}}
+	if err := writeCSV(s, filename); err != nil {
+		t.Fatalf("writeCSV: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV: %v", err)
+	}
+	if want := [][]string{s.header, s.rows[0]}; !reflect.DeepEqual(records, want) {
+		t.Errorf("expected %v, got %v", want, records)
+	}
+}
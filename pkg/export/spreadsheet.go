@@ -0,0 +1,231 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// sheet is a table written as a CSV file or as a worksheet of an XLSX workbook
+type sheet struct {
+	name   string
+	header []string
+	rows   [][]string
+}
+
+// writeCSV writes the header and rows of a sheet to filename
+func writeCSV(s sheet, filename string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to prepare file path: %w", err)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file %s: %w", filename, err)
+	}
+	w := csv.NewWriter(file)
+	w.Write(s.header)
+	w.WriteAll(s.rows)
+	if err := w.Error(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write CSV file %s: %w", filename, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write CSV file %s: %w", filename, err)
+	}
+	return nil
+}
+
+// writeXLSX writes sheets as the worksheets of an XLSX workbook to filename
+func writeXLSX(sheets []sheet, filename string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to prepare file path: %w", err)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create XLSX file %s: %w", filename, err)
+	}
+	if err := encodeXLSX(file, sheets); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode XLSX file %s: %w", filename, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write XLSX file %s: %w", filename, err)
+	}
+	return nil
+}
+
+// Namespaces and content types of the SpreadsheetML parts written by encodeXLSX
+const (
+	xlsxMainNS       = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxRelNS        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xlsxPackageRelNS = "http://schemas.openxmlformats.org/package/2006/relationships"
+	xlsxContentNS    = "http://schemas.openxmlformats.org/package/2006/content-types"
+	xlsxContentType  = "application/vnd.openxmlformats-officedocument.spreadsheetml"
+	xmlHeader        = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+)
+
+// xlsxStyles has a regular (s="0") and a bold (s="1") cell format
+const xlsxStyles = xmlHeader + `<styleSheet xmlns="` + xlsxMainNS + `">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
+
+// encodeXLSX writes a minimal workbook: one worksheet per sheet with inline strings, a bold
+// frozen header row with an auto filter, and columns sized to their content
+func encodeXLSX(w io.Writer, sheets []sheet) error {
+	names := sheetNames(sheets)
+
+	var contentTypes, workbook, workbookRels strings.Builder
+	contentTypes.WriteString(xmlHeader + `<Types xmlns="` + xlsxContentNS + `">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="` + xlsxContentType + `.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="` + xlsxContentType + `.styles+xml"/>`)
+	workbook.WriteString(xmlHeader + `<workbook xmlns="` + xlsxMainNS + `" xmlns:r="` + xlsxRelNS + `"><sheets>`)
+	workbookRels.WriteString(xmlHeader + `<Relationships xmlns="` + xlsxPackageRelNS + `">`)
+	for i, name := range names {
+		contentTypes.WriteString(fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="%s.worksheet+xml"/>`, i+1, xlsxContentType))
+		workbook.WriteString(fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(name), i+1, i+1))
+		workbookRels.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="%s/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, xlsxRelNS, i+1))
+	}
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	workbookRels.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="%s/styles" Target="styles.xml"/></Relationships>`, len(names)+1, xlsxRelNS))
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", xmlHeader + `<Relationships xmlns="` + xlsxPackageRelNS + `"><Relationship Id="rId1" Type="` + xlsxRelNS + `/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, s := range sheets {
+		parts = append(parts, struct{ name, content string }{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), worksheetXML(s)})
+	}
+
+	archive := zip.NewWriter(w)
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// integerCell matches values written as numbers rather than text
+var integerCell = regexp.MustCompile(`^-?[0-9]{1,15}$`)
+
+// worksheetXML renders the worksheet part of a sheet
+func worksheetXML(s sheet) string {
+	columns := len(s.header)
+	for _, row := range s.rows {
+		columns = max(columns, len(row))
+	}
+
+	var ws strings.Builder
+	ws.WriteString(xmlHeader + `<worksheet xmlns="` + xlsxMainNS + `">`)
+	ws.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+
+	if columns > 0 {
+		ws.WriteString(`<cols>`)
+		for c := 0; c < columns; c++ {
+			width := 0
+			for _, row := range append([][]string{s.header}, s.rows...) {
+				if c < len(row) {
+					width = max(width, utf8.RuneCountInString(row[c]))
+				}
+			}
+			ws.WriteString(fmt.Sprintf(`<col min="%d" max="%d" width="%d" customWidth="1"/>`, c+1, c+1, min(width+2, 60)))
+		}
+		ws.WriteString(`</cols>`)
+	}
+
+	ws.WriteString(`<sheetData>`)
+	for r, row := range append([][]string{s.header}, s.rows...) {
+		ws.WriteString(fmt.Sprintf(`<row r="%d">`, r+1))
+		for c, value := range row {
+			ref := fmt.Sprintf("%s%d", columnName(c), r+1)
+			switch {
+			case r == 0:
+				ws.WriteString(fmt.Sprintf(`<c r="%s" s="1" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, xmlEscape(value)))
+			case value == "":
+				continue
+			case integerCell.MatchString(value):
+				ws.WriteString(fmt.Sprintf(`<c r="%s"><v>%s</v></c>`, ref, value))
+			default:
+				ws.WriteString(fmt.Sprintf(`<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, xmlEscape(value)))
+			}
+		}
+		ws.WriteString(`</row>`)
+	}
+	ws.WriteString(`</sheetData>`)
+
+	if columns > 0 {
+		ws.WriteString(fmt.Sprintf(`<autoFilter ref="A1:%s%d"/>`, columnName(columns-1), len(s.rows)+1))
+	}
+	ws.WriteString(`</worksheet>`)
+	return ws.String()
+}
+
+// columnName returns the letters of a 0-based column index: A, B, ..., Z, AA, ...
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// sheetNames returns valid, distinct worksheet names: at most 31 characters, without
+// []:*?/\ and unique regardless of case
+func sheetNames(sheets []sheet) []string {
+	const maxLength = 31
+	invalid := strings.NewReplacer("[", "(", "]", ")", ":", "_", "*", "_", "?", "_", "/", "_", "\\", "_")
+	truncate := func(name string, length int) string {
+		runes := []rune(name)
+		if len(runes) > length {
+			runes = runes[:length]
+		}
+		return string(runes)
+	}
+
+	names := make([]string, len(sheets))
+	used := make(map[string]bool)
+	for i, s := range sheets {
+		base := truncate(strings.TrimSpace(invalid.Replace(s.name)), maxLength)
+		if base == "" {
+			base = fmt.Sprintf("Sheet%d", i+1)
+		}
+		name := base
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			name = truncate(base, maxLength-len(suffix)) + suffix
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+// xmlEscape escapes text for XML content and attribute values
+func xmlEscape(text string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(text))
+	return escaped.String()
+}
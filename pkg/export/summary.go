@@ -0,0 +1,147 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tsocial/ui-version-mapping/pkg/analyzer"
+	"github.com/tsocial/ui-version-mapping/pkg/diagram"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+)
+
+// GenerateSummaryReport creates a Markdown summary of all analyses written for a config
+func (e *Exporter) GenerateSummaryReport(configID int, leadSource string) error {
+	var report strings.Builder
+
+	report.WriteString(fmt.Sprintf("# Complete Analysis Report - Config %d\n\n", configID))
+	report.WriteString(fmt.Sprintf("**Lead Source:** %s\n", leadSource))
+	report.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+
+	// Read A/B Testing Analysis
+	if abData, err := os.ReadFile(e.layout.ABTestingJSON(configID, leadSource)); err == nil {
+		var abAnalysis analyzer.ABTestingAnalysisResult
+		if json.Unmarshal(abData, &abAnalysis) == nil {
+			report.WriteString("## A/B Testing Analysis\n\n")
+			report.WriteString(fmt.Sprintf("- **Total A/B Testing Groups:** %d\n", len(abAnalysis.ABTestingGroups)))
+
+			for i, group := range abAnalysis.ABTestingGroups {
+				report.WriteString(fmt.Sprintf("- **Group %d:** %s (%d variants, total weight: %d)\n",
+					i+1, group.GroupName, len(group.Variants), group.TotalWeight))
+				if group.Strategy != "" {
+					report.WriteString(fmt.Sprintf("  - Grouped by: %s\n", group.Strategy))
+				}
+
+				for j, variant := range group.Variants {
+					report.WriteString(fmt.Sprintf("  - Variant %d: Config %d (weight: %d, %d steps)\n",
+						j+1, variant.ConfigID, variant.Weight, len(variant.UIFlow)))
+					if len(variant.FlowDiff) == 0 {
+						continue
+					}
+					report.WriteString(fmt.Sprintf("    - Flow diff vs original: %s\n", analyzer.FlowDiffSummary(variant.FlowDiff)))
+					for _, op := range variant.FlowDiff {
+						report.WriteString(fmt.Sprintf("      - %s\n", op.String()))
+					}
+				}
+			}
+
+			report.WriteString(fmt.Sprintf("- **Normal Results:** %d configs\n\n", len(abAnalysis.NormalResults)))
+		}
+	}
+
+	// Read Journey Analysis
+	if journeyData, err := os.ReadFile(e.layout.JourneyJSON(configID, leadSource)); err == nil {
+		var journeyTemplate journey.JourneyTemplate
+		if json.Unmarshal(journeyData, &journeyTemplate) == nil {
+			report.WriteString("## Journey Analysis\n\n")
+			report.WriteString(fmt.Sprintf("- **Total Journeys:** %d\n", len(journeyTemplate.Journeys)))
+			report.WriteString(fmt.Sprintf("- **Related Config IDs:** %v\n\n", journeyTemplate.RelatedConfigIDs))
+
+			// Group journeys by flow type
+			flowTypes := make(map[string]int)
+			var flowTypeNames []string
+			for _, j := range journeyTemplate.Journeys {
+				if flowTypes[j.FlowType] == 0 {
+					flowTypeNames = append(flowTypeNames, j.FlowType)
+				}
+				flowTypes[j.FlowType]++
+			}
+			sort.Strings(flowTypeNames)
+
+			report.WriteString("### Journey Flow Types:\n")
+			for _, flowType := range flowTypeNames {
+				report.WriteString(fmt.Sprintf("- **%s:** %d journeys\n", flowType, flowTypes[flowType]))
+			}
+			report.WriteString("\n")
+		}
+	}
+
+	// Config files skipped and config IDs duplicated under lenient loading
+	diagnostics := e.service.LoadDiagnostics()
+	duplicates := e.service.LoadDuplicates()
+	if len(diagnostics) > 0 || len(duplicates) > 0 {
+		report.WriteString("## Load Warnings\n\n")
+		if len(diagnostics) > 0 {
+			report.WriteString(fmt.Sprintf("%d config files could not be loaded and were left out of the analysis:\n\n", len(diagnostics)))
+			for _, d := range diagnostics {
+				report.WriteString(fmt.Sprintf("- ⚠️ `%s`\n", d))
+			}
+			report.WriteString("\n")
+		}
+		if len(duplicates) > 0 {
+			report.WriteString(fmt.Sprintf("%d config IDs are declared by more than one file:\n\n", len(duplicates)))
+			for _, d := range duplicates {
+				report.WriteString(fmt.Sprintf("- ⚠️ %s\n", d))
+			}
+			report.WriteString("\n")
+		}
+	}
+
+	// Generated Files Section
+	report.WriteString("## Generated Files\n\n")
+
+	type generatedFile struct {
+		name        string
+		description string
+	}
+	files := []generatedFile{
+		{e.layout.ABTestingJSON(configID, leadSource), "A/B Testing Analysis (JSON)"},
+		{e.layout.JourneyJSON(configID, leadSource), "Journey Analysis (JSON)"},
+		{e.layout.JourneyFlowPuml(configID, leadSource), "Journey Flow Diagram (PlantUML)"},
+		{e.layout.JourneyFlowPNG(configID, leadSource), "Journey Flow Diagram (PNG)"},
+		{e.layout.JourneyFlowSVG(configID, leadSource), "Journey Flow Diagram (SVG)"},
+		{e.layout.ABTestingPNG(configID, leadSource), "A/B Testing Groups Diagram (PNG)"},
+		{e.layout.UIVersionMappingCSV(configID, leadSource), "UI Version Mapping (CSV)"},
+		{e.layout.UIVersionMappingXLSX(configID, leadSource), "UI Version Mapping (XLSX)"},
+	}
+	for _, format := range e.diagrams {
+		if format == diagram.FormatMermaid {
+			files = append(files, generatedFile{e.layout.JourneyFlowMermaid(configID, leadSource), "Journey Flow Diagram (Mermaid)"})
+		}
+	}
+
+	for _, file := range files {
+		if _, err := os.Stat(file.name); err == nil {
+			report.WriteString(fmt.Sprintf("- ✅ **%s:** `%s`\n", file.description, file.name))
+		} else {
+			report.WriteString(fmt.Sprintf("- ❌ **%s:** `%s` (not generated)\n", file.description, file.name))
+		}
+	}
+
+	// Write summary report
+	summaryFilename := e.layout.SummaryReport(configID, leadSource)
+	if err := os.MkdirAll(filepath.Dir(summaryFilename), 0755); err != nil {
+		return fmt.Errorf("failed to prepare summary file path: %w", err)
+	}
+
+	if err := os.WriteFile(summaryFilename, []byte(report.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write summary report %s: %w", summaryFilename, err)
+	}
+
+	fmt.Printf("Summary report written to %s\n", summaryFilename)
+	return nil
+}
@@ -0,0 +1,60 @@
+package journey
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// Builder generates journey templates from lender configs served by a ConfigProvider
+type Builder struct {
+	provider      config.ConfigProvider
+	stepTemplates *StepTemplateSet
+	catalogue     *StepCatalogue
+}
+
+// NewBuilder creates a journey builder backed by provider
+func NewBuilder(provider config.ConfigProvider) *Builder {
+	return &Builder{
+		provider: provider,
+	}
+}
+
+// WithStepTemplates sets the step templates used for journeys between configs
+func (b *Builder) WithStepTemplates(templates *StepTemplateSet) *Builder {
+	b.stepTemplates = templates
+	return b
+}
+
+// WithStepCatalogue sets the step catalogue that marks decision steps and supplies default UI versions
+func (b *Builder) WithStepCatalogue(catalogue *StepCatalogue) *Builder {
+	b.catalogue = catalogue
+	return b
+}
+
+// Build creates the journey template of a source config towards its related configs.
+// Related configs that cannot be loaded are reported and skipped.
+func (b *Builder) Build(ctx context.Context, sourceConfigID int, leadSource string, relatedConfigs []config.RelatedConfigResult) (*JourneyTemplate, error) {
+	sourceConfig, err := b.provider.LoadConfig(ctx, sourceConfigID, leadSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load source config %d: %w", sourceConfigID, err)
+	}
+
+	targetConfigs := make(map[int]*config.LenderConfig)
+	for _, related := range relatedConfigs {
+		if related.IsABTesting {
+			continue
+		}
+
+		// Related configs may not carry the requested lead_source, so load by ID only
+		targetConfig, err := b.provider.LoadConfig(ctx, related.ConfigID, "")
+		if err != nil {
+			fmt.Printf("Warning: failed to load related config %d: %v\n", related.ConfigID, err)
+			continue
+		}
+		targetConfigs[related.ConfigID] = targetConfig
+	}
+
+	return generateJourneyTemplate(sourceConfig, targetConfigs, relatedConfigs, b.stepTemplates, b.catalogue), nil
+}
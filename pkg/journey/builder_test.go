@@ -0,0 +1,88 @@
+package journey
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// memoryProvider serves configs from memory for builder tests
+type memoryProvider struct {
+	configs map[int]*config.LenderConfig
+}
+
+func (p *memoryProvider) LoadConfigs(ctx context.Context, path string) ([]*config.LenderConfig, error) {
+	var configs []*config.LenderConfig
+	for _, cfg := range p.configs {
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+func (p *memoryProvider) LoadConfig(ctx context.Context, configID int, leadSource string) (*config.LenderConfig, error) {
+	cfg, ok := p.configs[configID]
+	if !ok {
+		return nil, fmt.Errorf("config %d not found", configID)
+	}
+	return cfg, nil
+}
+
+func TestBuilderBuild(t *testing.T) {
+	provider := &memoryProvider{configs: map[int]*config.LenderConfig{
+		9054: {
+			ID:        9054,
+			Tags:      []config.Tag{{Name: "flow_type", Value: "collect"}},
+			UIVersion: "v9.1.5.0",
+			UIFlow:    []string{"otp", "app_form.basic_info", "ekyc.selfie.active"},
+		},
+		9095: {
+			ID:        9095,
+			Tags:      []config.Tag{{Name: "flow_type", Value: "auto_pcb"}},
+			UIVersion: "v9.2.0.0",
+		},
+		9048: {
+			ID:        9048,
+			Tags:      []config.Tag{{Name: "flow_type", Value: "rejection"}},
+			UIVersion: "v9.0.0.0",
+		},
+	}}
+
+	related := []config.RelatedConfigResult{
+		{ConfigID: 9095, MatchReason: "different flow_type: auto_pcb"},
+		{ConfigID: 9048, MatchReason: "different flow_type: rejection"},
+		{ConfigID: 9100, IsABTesting: true},
+		{ConfigID: 9999, MatchReason: "same product_code"},
+	}
+
+	template, err := NewBuilder(provider).Build(context.Background(), 9054, "organic", related)
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	if got, want := fmt.Sprint(template.RelatedConfigIDs), "[9095 9048 9999]"; got != want {
+		t.Errorf("RelatedConfigIDs = %s, want %s", got, want)
+	}
+
+	// Self-loop plus one journey per loadable related config
+	if len(template.Journeys) != 3 {
+		t.Fatalf("got %d journeys, want 3", len(template.Journeys))
+	}
+
+	auto := template.Journeys[1]
+	if auto.ID != "from_9054_to_9095" || auto.FlowType != "collect_to_auto_pcb" {
+		t.Errorf("unexpected auto journey %s (%s)", auto.ID, auto.FlowType)
+	}
+	if len(auto.Steps) != 20 {
+		t.Errorf("auto journey has %d steps, want 20", len(auto.Steps))
+	}
+	if auto.Condition != "flow_routing_condition == true" {
+		t.Errorf("auto journey condition = %q", auto.Condition)
+	}
+
+	rejection := template.Journeys[2]
+	if last := rejection.Steps[len(rejection.Steps)-1]; last.Name != "failure" || last.MainUIVersion != "v9.0.0.0" {
+		t.Errorf("rejection journey should end in failure on target UI version, got %+v", last)
+	}
+}
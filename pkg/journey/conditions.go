@@ -0,0 +1,37 @@
+package journey
+
+import (
+	"fmt"
+
+	"github.com/tsocial/ui-version-mapping/pkg/condition"
+)
+
+// ValidateConditions parses every journey and step condition of a template and checks them
+// against schema (nil skips the attribute checks). Errors carry the journey and step they belong to.
+func ValidateConditions(template *JourneyTemplate, schema condition.Schema) []error {
+	var errs []error
+
+	check := func(location, expr string) {
+		node, err := condition.Parse(expr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", location, err))
+			return
+		}
+		for _, err := range condition.Validate(node, schema) {
+			errs = append(errs, fmt.Errorf("%s: %w", location, err))
+		}
+	}
+
+	for _, j := range template.Journeys {
+		if j.Condition != "" {
+			check(fmt.Sprintf("journey %s", j.ID), j.Condition)
+		}
+		for _, step := range j.Steps {
+			for _, c := range step.SubUIVersionByConditions {
+				check(fmt.Sprintf("journey %s step %d (%s)", j.ID, step.ID, step.Name), c.Condition)
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,191 @@
+package journey
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// SearchTypeLenderConfigID is the search type of a journey template built for a lender config
+const SearchTypeLenderConfigID = "lender_config_id"
+
+// GenerateJourneyTemplate creates a complete journey template for a source config.
+// targetConfigs holds the loaded related configs keyed by ID; related configs missing
+// from the map are skipped, as are A/B testing variants. A nil stepTemplates uses the
+// built-in step templates. Steps are annotated with the built-in step catalogue.
+func GenerateJourneyTemplate(sourceConfig *config.LenderConfig, targetConfigs map[int]*config.LenderConfig, relatedConfigs []config.RelatedConfigResult, stepTemplates *StepTemplateSet) *JourneyTemplate {
+	return generateJourneyTemplate(sourceConfig, targetConfigs, relatedConfigs, stepTemplates, nil)
+}
+
+// generateJourneyTemplate is GenerateJourneyTemplate with a step catalogue; nil uses the built-in one
+func generateJourneyTemplate(sourceConfig *config.LenderConfig, targetConfigs map[int]*config.LenderConfig, relatedConfigs []config.RelatedConfigResult, stepTemplates *StepTemplateSet, catalogue *StepCatalogue) *JourneyTemplate {
+	if stepTemplates == nil {
+		stepTemplates = DefaultStepTemplates()
+	}
+	if catalogue == nil {
+		catalogue = DefaultStepCatalogue()
+	}
+
+	var relatedConfigIDs []int
+	var journeys []Journey
+
+	// Add self-loop journey (standard flow)
+	standardSteps := GenerateConfigJourneySteps(sourceConfig)
+	catalogue.Annotate(standardSteps)
+	standardJourney := GenerateJourneyFromTemplate(
+		sourceConfig.ID,
+		sourceConfig.ID,
+		"normal",
+		"",
+		"Normal flow",
+		standardSteps,
+	)
+	journeys = append(journeys, standardJourney)
+
+	// Generate journeys for related configs
+	for _, relatedConfig := range relatedConfigs {
+		if relatedConfig.IsABTesting {
+			continue // Skip A/B testing variants for journey generation
+		}
+
+		relatedConfigIDs = append(relatedConfigIDs, relatedConfig.ConfigID)
+
+		targetConfig, ok := targetConfigs[relatedConfig.ConfigID]
+		if !ok || targetConfig == nil {
+			continue
+		}
+
+		// Generate journey based on flow type and match reason
+		flowType := DetermineFlowType(sourceConfig, targetConfig, relatedConfig.MatchReason)
+		condition := GenerateConditionFromMatchReason(relatedConfig.MatchReason)
+		description := GenerateDescriptionFromFlowType(flowType, relatedConfig.Name)
+
+		// Generate full journey steps combining source and target flows
+		targetSteps := stepTemplates.GenerateSteps(sourceConfig, targetConfig, flowType)
+		catalogue.Annotate(targetSteps)
+
+		journeys = append(journeys, GenerateJourneyFromTemplate(
+			sourceConfig.ID,
+			relatedConfig.ConfigID,
+			flowType,
+			condition,
+			description,
+			targetSteps,
+		))
+	}
+
+	return &JourneyTemplate{
+		SearchValue:      int64(sourceConfig.ID),
+		SearchType:       SearchTypeLenderConfigID,
+		RelatedConfigIDs: relatedConfigIDs,
+		Journeys:         journeys,
+	}
+}
+
+// GenerateJourneyFromTemplate creates a journey section based on template data
+func GenerateJourneyFromTemplate(sourceConfigID int, targetConfigID int, flowType string, condition string, description string, steps []Step) Journey {
+	return Journey{
+		ID:                 fmt.Sprintf("from_%d_to_%d", sourceConfigID, targetConfigID),
+		FlowType:           flowType,
+		FromLenderConfigID: sourceConfigID,
+		ToLenderConfigID:   targetConfigID,
+		Active:             true,
+		Condition:          condition,
+		Description:        description,
+		Steps:              steps,
+	}
+}
+
+// GenerateStandardJourneySteps creates standard journey steps based on UI flow
+func GenerateStandardJourneySteps(uiFlow []string, mainUIVersion string) []Step {
+	var steps []Step
+
+	for i, stepName := range uiFlow {
+		steps = append(steps, Step{
+			ID:                       i,
+			Name:                     stepName,
+			MainUIVersion:            mainUIVersion,
+			SubUIVersion:             "",
+			SubUIVersionByConditions: []SubUIVersionByCondition{},
+		})
+	}
+
+	return steps
+}
+
+// GenerateFullJourneySteps creates complete journey steps combining source and target flows
+// using the built-in step templates
+func GenerateFullJourneySteps(sourceConfig, targetConfig *config.LenderConfig, flowType string) []Step {
+	return DefaultStepTemplates().GenerateSteps(sourceConfig, targetConfig, flowType)
+}
+
+// newStep builds a step, normalizing nil conditions to an empty slice for JSON output
+func newStep(id int, name, mainUIVersion, subUIVersion string, conditions []SubUIVersionByCondition) Step {
+	if conditions == nil {
+		conditions = []SubUIVersionByCondition{}
+	}
+
+	return Step{
+		ID:                       id,
+		Name:                     name,
+		MainUIVersion:            mainUIVersion,
+		SubUIVersion:             subUIVersion,
+		SubUIVersionByConditions: conditions,
+	}
+}
+
+// DetermineFlowType determines the flow type based on source and target configs
+func DetermineFlowType(sourceConfig, targetConfig *config.LenderConfig, matchReason string) string {
+	sourceFlowType := config.GetFlowTypeFromTags(sourceConfig.Tags)
+	targetFlowType := config.GetFlowTypeFromTags(targetConfig.Tags)
+
+	// If same flow type, it's a normal flow
+	if sourceFlowType == targetFlowType {
+		return "normal"
+	}
+
+	// Generate flow type based on transition
+	return fmt.Sprintf("%s_to_%s", sourceFlowType, targetFlowType)
+}
+
+// GenerateConditionFromMatchReason creates a condition string based on match reason
+func GenerateConditionFromMatchReason(matchReason string) string {
+	// Parse match reason to generate appropriate conditions
+	if strings.Contains(matchReason, "different flow_type") {
+		return "flow_routing_condition == true"
+	}
+	if strings.Contains(matchReason, "same product_code") {
+		return "product_eligibility == true"
+	}
+	if strings.Contains(matchReason, "same lead_source") {
+		return "lead_source_match == true"
+	}
+	if strings.Contains(matchReason, "shared telco_code") {
+		return "telco_compatibility == true"
+	}
+
+	return "routing_condition == true"
+}
+
+// GenerateDescriptionFromFlowType creates a human-readable description
+func GenerateDescriptionFromFlowType(flowType, configName string) string {
+	switch {
+	case strings.Contains(flowType, "rejection"):
+		return "Rejection flow"
+	case strings.Contains(flowType, "auto"):
+		return "Automated flow"
+	case strings.Contains(flowType, "semi"):
+		return "Semi-automated flow"
+	case strings.Contains(flowType, "manual"):
+		return "Manual review flow"
+	case strings.Contains(flowType, "cif"):
+		return "CIF verification flow"
+	case strings.Contains(flowType, "diff"):
+		return "Different information flow"
+	case flowType == "normal":
+		return "Normal flow"
+	default:
+		return fmt.Sprintf("Flow to %s", configName)
+	}
+}
@@ -0,0 +1,164 @@
+package journey
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tsocial/ui-version-mapping/pkg/condition"
+)
+
+// FlowTypeAttribute is the simulation attribute that selects a journey by flow type
+// (the flow outcome, e.g. "normal" or "auto_to_semi") instead of its routing condition
+const FlowTypeAttribute = "flow_type"
+
+// Simulation is the concrete journey a user with the given attributes goes through
+type Simulation struct {
+	ConfigID      int                  `json:"config_id"`
+	Attributes    condition.Attributes `json:"attributes"`
+	JourneyID     string               `json:"journey_id"`
+	FlowType      string               `json:"flow_type"`
+	JourneyReason string               `json:"journey_reason"`
+	Steps         []ResolvedStep       `json:"steps"`
+	Warnings      []string             `json:"warnings,omitempty"`
+}
+
+// ResolvedStep is a step with its UI version resolved for the simulated attributes
+type ResolvedStep struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	MainUIVersion string `json:"main_ui_version"`
+	SubUIVersion  string `json:"sub_ui_version"`
+	Reason        string `json:"reason"`
+}
+
+// UIVersion returns the resolved UI version of the step as "main" or "main/sub"
+func (s ResolvedStep) UIVersion() string {
+	if s.SubUIVersion == "" {
+		return s.MainUIVersion
+	}
+	return s.MainUIVersion + "/" + s.SubUIVersion
+}
+
+// Simulate walks the journeys of a template that start at its source config and resolves
+// one concrete list of steps for attrs.
+//
+// The journey is chosen by the flow_type attribute when it is set, otherwise by the
+// first journey to another config whose condition holds; the self-loop journey is used
+// when none does. Within a step the first matching sub_ui_version_by_conditions entry
+// wins over the step's default sub UI version. Conditions referencing attributes that
+// are not set are treated as not matching and reported in Warnings.
+func Simulate(template *JourneyTemplate, attrs condition.Attributes) (*Simulation, error) {
+	if template == nil {
+		return nil, errors.New("journey template is nil")
+	}
+	if attrs == nil {
+		attrs = condition.Attributes{}
+	}
+
+	sim := &Simulation{
+		ConfigID:   int(template.SearchValue),
+		Attributes: attrs,
+		Steps:      []ResolvedStep{},
+	}
+
+	j, reason, err := sim.selectJourney(template.Journeys)
+	if err != nil {
+		return nil, err
+	}
+
+	sim.JourneyID = j.ID
+	sim.FlowType = j.FlowType
+	sim.JourneyReason = reason
+
+	for _, step := range j.Steps {
+		sim.Steps = append(sim.Steps, sim.resolveStep(j.ID, step))
+	}
+
+	return sim, nil
+}
+
+// selectJourney picks the journey taken from the source config
+func (sim *Simulation) selectJourney(journeys []Journey) (*Journey, string, error) {
+	var selfLoop *Journey
+	var candidates []*Journey
+	for i := range journeys {
+		j := &journeys[i]
+		if j.FromLenderConfigID != sim.ConfigID || !j.Active {
+			continue
+		}
+		if j.ToLenderConfigID == sim.ConfigID {
+			if selfLoop == nil {
+				selfLoop = j
+			}
+			continue
+		}
+		candidates = append(candidates, j)
+	}
+
+	if flowType, ok := sim.Attributes[FlowTypeAttribute]; ok {
+		if selfLoop != nil && selfLoop.FlowType == flowType {
+			return selfLoop, fmt.Sprintf("%s=%s", FlowTypeAttribute, flowType), nil
+		}
+		for _, j := range candidates {
+			if j.FlowType == flowType {
+				return j, fmt.Sprintf("%s=%s", FlowTypeAttribute, flowType), nil
+			}
+		}
+		return nil, "", fmt.Errorf("no journey from config %d with flow type %q", sim.ConfigID, flowType)
+	}
+
+	for _, j := range candidates {
+		if j.Condition == "" {
+			continue
+		}
+		if sim.evalCondition(fmt.Sprintf("journey %s", j.ID), j.Condition) {
+			return j, fmt.Sprintf("condition %q matched", j.Condition), nil
+		}
+	}
+
+	if selfLoop == nil {
+		return nil, "", fmt.Errorf("no journey from config %d matches the given attributes", sim.ConfigID)
+	}
+	return selfLoop, "no routing condition matched, normal flow", nil
+}
+
+// resolveStep resolves the sub UI version of a step
+func (sim *Simulation) resolveStep(journeyID string, step Step) ResolvedStep {
+	resolved := ResolvedStep{
+		ID:            step.ID,
+		Name:          step.Name,
+		MainUIVersion: step.MainUIVersion,
+	}
+
+	location := fmt.Sprintf("journey %s step %d (%s)", journeyID, step.ID, step.Name)
+	for _, c := range step.SubUIVersionByConditions {
+		if sim.evalCondition(location, c.Condition) {
+			resolved.SubUIVersion = c.SubUIVersion
+			resolved.Reason = fmt.Sprintf("condition %q matched", c.Condition)
+			return resolved
+		}
+	}
+
+	switch {
+	case step.SubUIVersion != "":
+		resolved.SubUIVersion = step.SubUIVersion
+		resolved.Reason = "default sub UI version"
+	case len(step.SubUIVersionByConditions) > 0:
+		resolved.Reason = "no condition matched, main UI version"
+	default:
+		resolved.Reason = "main UI version"
+	}
+
+	return resolved
+}
+
+// evalCondition evaluates expr against the simulation attributes; invalid expressions
+// and missing attributes count as not matching and are recorded as warnings
+func (sim *Simulation) evalCondition(location, expr string) bool {
+	ok, err := condition.EvalString(expr, sim.Attributes)
+	if err != nil {
+		sim.Warnings = append(sim.Warnings, fmt.Sprintf("%s: %v", location, err))
+		return false
+	}
+	return ok
+}
@@ -0,0 +1,86 @@
+package journey
+
+import (
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/condition"
+)
+
+func simulationTemplate() *JourneyTemplate {
+	return &JourneyTemplate{
+		SearchValue: 9054,
+		SearchType:  SearchTypeLenderConfigID,
+		Journeys: []Journey{
+			GenerateJourneyFromTemplate(9054, 9054, "normal", "", "Normal flow", []Step{
+				newStep(0, "otp", "v9.1.5.0", "v1.0-c1", nil),
+				newStep(1, "inform.success", "v9.1.5.0", "", []SubUIVersionByCondition{
+					{Condition: "communication_call=success, lead_source=organic", SubUIVersion: "v1.1-c1"},
+					{Condition: "communication_call=success", SubUIVersion: "v1.0-c1"},
+				}),
+			}),
+			GenerateJourneyFromTemplate(9054, 9013, "collect_to_auto", "flow_routing_condition == true", "Automated flow", []Step{
+				newStep(0, "esign.review", "v9.1.4.0", "v1.0-auto-nfc", []SubUIVersionByCondition{
+					{Condition: "telco_code=viettel", SubUIVersion: "v1.1-auto-nfc"},
+				}),
+			}),
+		},
+	}
+}
+
+func TestSimulateResolvesStepVersions(t *testing.T) {
+	sim, err := Simulate(simulationTemplate(), condition.Attributes{
+		"lead_source":            "organic",
+		"communication_call":     "success",
+		"flow_routing_condition": "false",
+	})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	if sim.JourneyID != "from_9054_to_9054" {
+		t.Fatalf("expected the normal journey, got %s", sim.JourneyID)
+	}
+
+	want := []string{"v9.1.5.0/v1.0-c1", "v9.1.5.0/v1.1-c1"}
+	if len(sim.Steps) != len(want) {
+		t.Fatalf("expected %d steps, got %d", len(want), len(sim.Steps))
+	}
+	for i, step := range sim.Steps {
+		if step.UIVersion() != want[i] {
+			t.Errorf("step %s: expected %s, got %s (%s)", step.Name, want[i], step.UIVersion(), step.Reason)
+		}
+	}
+	if len(sim.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", sim.Warnings)
+	}
+}
+
+func TestSimulateSelectsRoutedJourney(t *testing.T) {
+	sim, err := Simulate(simulationTemplate(), condition.Attributes{"flow_routing_condition": "true"})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if sim.JourneyID != "from_9054_to_9013" {
+		t.Fatalf("expected the routed journey, got %s", sim.JourneyID)
+	}
+
+	// telco_code is not set, so the step falls back to its default and the miss is reported
+	if got := sim.Steps[0].UIVersion(); got != "v9.1.4.0/v1.0-auto-nfc" {
+		t.Errorf("expected default sub UI version, got %s", got)
+	}
+	if len(sim.Warnings) != 1 {
+		t.Errorf("expected one missing attribute warning, got %v", sim.Warnings)
+	}
+
+	sim, err = Simulate(simulationTemplate(), condition.Attributes{FlowTypeAttribute: "collect_to_auto", "telco_code": "viettel"})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if got := sim.Steps[0].UIVersion(); got != "v9.1.4.0/v1.1-auto-nfc" {
+		t.Errorf("expected conditional sub UI version, got %s", got)
+	}
+
+	if _, err := Simulate(simulationTemplate(), condition.Attributes{FlowTypeAttribute: "rejection"}); err == nil {
+		t.Error("expected an error for an unknown flow type")
+	}
+}
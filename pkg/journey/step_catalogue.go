@@ -0,0 +1,203 @@
+package journey
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Step namespaces, the part of a step name before the first dot
+const (
+	NamespaceOTP        = "otp"
+	NamespaceAppForm    = "app_form"
+	NamespaceEKYC       = "ekyc"
+	NamespaceAppraising = "appraising"
+	NamespaceESign      = "esign"
+	NamespaceInform     = "inform"
+	NamespaceCIF        = "cif"
+)
+
+// Namespaces lists the known step namespaces
+var Namespaces = []string{
+	NamespaceOTP, NamespaceAppForm, NamespaceEKYC, NamespaceAppraising, NamespaceESign, NamespaceInform, NamespaceCIF,
+}
+
+//go:embed templates/step_catalogue.json
+var defaultStepCatalogueJSON []byte
+
+var (
+	defaultStepCatalogue     *StepCatalogue
+	defaultStepCatalogueOnce sync.Once
+)
+
+// StepCatalogue describes the known ui_flow steps
+type StepCatalogue struct {
+	Version int              `json:"version"`
+	Steps   []StepDefinition `json:"steps"`
+
+	index map[string]int
+}
+
+// StepDefinition describes a ui_flow step.
+//
+// Successors restricts the steps that may follow the step in a ui_flow; no successors
+// means any step may follow. DefaultUIVersion is the sub UI version of the step when
+// neither the step templates nor the config's ui_flow_settings set one.
+type StepDefinition struct {
+	Name             string   `json:"name"`
+	Namespace        string   `json:"namespace,omitempty"`
+	Description      string   `json:"description,omitempty"`
+	Decision         bool     `json:"decision,omitempty"`
+	DefaultUIVersion string   `json:"default_ui_version,omitempty"`
+	Successors       []string `json:"successors,omitempty"`
+}
+
+// DefaultStepCatalogue returns the built-in step catalogue. The result is shared and must not be modified.
+func DefaultStepCatalogue() *StepCatalogue {
+	defaultStepCatalogueOnce.Do(func() {
+		catalogue, err := ParseStepCatalogue(defaultStepCatalogueJSON)
+		if err != nil {
+			panic(fmt.Sprintf("invalid built-in step catalogue: %v", err))
+		}
+		defaultStepCatalogue = catalogue
+	})
+	return defaultStepCatalogue
+}
+
+// LoadStepCatalogue reads and validates a step catalogue file
+func LoadStepCatalogue(filename string) (*StepCatalogue, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read step catalogue %s: %w", filename, err)
+	}
+
+	catalogue, err := ParseStepCatalogue(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid step catalogue %s: %w", filename, err)
+	}
+
+	return catalogue, nil
+}
+
+// ParseStepCatalogue decodes and validates a step catalogue from JSON. Steps without a
+// namespace get the prefix of their name.
+func ParseStepCatalogue(data []byte) (*StepCatalogue, error) {
+	var catalogue StepCatalogue
+	if err := json.Unmarshal(data, &catalogue); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal step catalogue: %w", err)
+	}
+
+	for i := range catalogue.Steps {
+		if catalogue.Steps[i].Namespace == "" {
+			catalogue.Steps[i].Namespace = StepNamespace(catalogue.Steps[i].Name)
+		}
+	}
+
+	if err := catalogue.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &catalogue, nil
+}
+
+// Validate checks that steps are named once, use known namespaces and only list known successors
+func (c *StepCatalogue) Validate() error {
+	c.index = make(map[string]int, len(c.Steps))
+	for i, step := range c.Steps {
+		if step.Name == "" {
+			return fmt.Errorf("steps[%d]: name is required", i)
+		}
+		if _, ok := c.index[step.Name]; ok {
+			return fmt.Errorf("step %q is declared twice", step.Name)
+		}
+		if !isNamespace(step.Namespace) {
+			return fmt.Errorf("step %q: unknown namespace %q", step.Name, step.Namespace)
+		}
+		c.index[step.Name] = i
+	}
+
+	for _, step := range c.Steps {
+		for _, successor := range step.Successors {
+			if _, ok := c.index[successor]; !ok {
+				return fmt.Errorf("step %q: unknown successor %q", step.Name, successor)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Lookup returns the definition of a step
+func (c *StepCatalogue) Lookup(name string) (StepDefinition, bool) {
+	if c.index == nil {
+		c.Validate()
+	}
+	i, ok := c.index[name]
+	if !ok {
+		return StepDefinition{}, false
+	}
+	return c.Steps[i], true
+}
+
+// Names returns the step names in catalogue order
+func (c *StepCatalogue) Names() []string {
+	names := make([]string, 0, len(c.Steps))
+	for _, step := range c.Steps {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
+// IsDecision reports whether a step is a decision step; unknown steps are not
+func (c *StepCatalogue) IsDecision(name string) bool {
+	step, ok := c.Lookup(name)
+	return ok && step.Decision
+}
+
+// AllowsTransition reports whether to may follow from in a ui_flow. Transitions from
+// unknown steps or steps without successors are allowed.
+func (c *StepCatalogue) AllowsTransition(from, to string) bool {
+	step, ok := c.Lookup(from)
+	if !ok || len(step.Successors) == 0 {
+		return true
+	}
+	for _, successor := range step.Successors {
+		if successor == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Annotate marks decision steps and fills the catalogue's default UI version into steps
+// that have neither a sub UI version nor conditional ones
+func (c *StepCatalogue) Annotate(steps []Step) {
+	for i := range steps {
+		definition, ok := c.Lookup(steps[i].Name)
+		if !ok {
+			continue
+		}
+		steps[i].Decision = definition.Decision
+		if steps[i].SubUIVersion == "" && len(steps[i].SubUIVersionByConditions) == 0 {
+			steps[i].SubUIVersion = definition.DefaultUIVersion
+		}
+	}
+}
+
+// StepNamespace returns the part of a step name before the first dot
+func StepNamespace(name string) string {
+	namespace, _, _ := strings.Cut(name, ".")
+	return namespace
+}
+
+func isNamespace(namespace string) bool {
+	for _, known := range Namespaces {
+		if namespace == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package journey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func TestDefaultStepCatalogue(t *testing.T) {
+	catalogue := DefaultStepCatalogue()
+
+	// Every step the templates generate must be known to the catalogue
+	for _, name := range DefaultStepTemplates().StepNames() {
+		if _, ok := catalogue.Lookup(name); !ok {
+			t.Errorf("step %s of the step templates is missing from the catalogue", name)
+		}
+	}
+
+	failure, ok := catalogue.Lookup("failure")
+	if !ok || failure.Namespace != NamespaceInform {
+		t.Errorf("expected failure in the inform namespace, got %+v", failure)
+	}
+	if !catalogue.IsDecision("appraising.quick_approval") || catalogue.IsDecision("otp") || catalogue.IsDecision("no.such.step") {
+		t.Error("unexpected decision steps")
+	}
+	if !catalogue.AllowsTransition("otp", "app_form.basic_info") || catalogue.AllowsTransition("otp", "esign.review") {
+		t.Error("unexpected successors of otp")
+	}
+	// The ui_flow of the flash selfie variant (config 9101) in the reference data
+	flow := []string{"otp", "app_form.basic_info", "appraising.quick_approval", "app_form.personal_info", "ekyc.selfie.flash",
+		"appraising.second_approval", "ekyc.id_card", "ekyc.confirm", "appraising.third_approval", "appraising.fourth_approval"}
+	for i := 1; i < len(flow); i++ {
+		if !catalogue.AllowsTransition(flow[i-1], flow[i]) {
+			t.Errorf("expected %s to be allowed after %s", flow[i], flow[i-1])
+		}
+	}
+	if !catalogue.AllowsTransition("failure", "otp") || !catalogue.AllowsTransition("no.such.step", "otp") {
+		t.Error("steps without successors should allow any transition")
+	}
+}
+
+func TestAnnotateSteps(t *testing.T) {
+	steps := []Step{
+		newStep(0, "app_form.personal_info", "v1", "", nil),
+		newStep(1, "esign.intro", "v1", "", []SubUIVersionByCondition{{Condition: "x", SubUIVersion: "v2"}}),
+		newStep(2, "appraising.fifth_approval", "v1", "v3", nil),
+		newStep(3, "unknown", "v1", "", nil),
+	}
+	DefaultStepCatalogue().Annotate(steps)
+
+	if steps[0].SubUIVersion != "v1.0-c1" || steps[0].Decision {
+		t.Errorf("expected the default UI version, got %+v", steps[0])
+	}
+	if steps[1].SubUIVersion != "" {
+		t.Errorf("conditional steps should keep an empty sub UI version, got %+v", steps[1])
+	}
+	if steps[2].SubUIVersion != "v3" || !steps[2].Decision {
+		t.Errorf("expected a decision step keeping its sub UI version, got %+v", steps[2])
+	}
+	if steps[3].SubUIVersion != "" || steps[3].Decision {
+		t.Errorf("unknown steps should be left alone, got %+v", steps[3])
+	}
+}
+
+func TestGenerateJourneyTemplateMarksDecisionSteps(t *testing.T) {
+	source := &config.LenderConfig{ID: 1, UIVersion: "src", UIFlow: []string{"otp", "app_form.basic_info"}}
+	target := &config.LenderConfig{ID: 2, UIVersion: "tgt", UIFlow: []string{"cif.confirm", "appraising.cif"}}
+	related := []config.RelatedConfigResult{{ConfigID: 2, Name: "cif", MatchReason: "collect_to_cif"}}
+
+	template := GenerateJourneyTemplate(source, map[int]*config.LenderConfig{2: target}, related, nil)
+
+	var decisions []string
+	for _, j := range template.Journeys {
+		for _, step := range j.Steps {
+			if step.Decision {
+				decisions = append(decisions, step.Name)
+			}
+		}
+	}
+	if strings.Join(decisions, ",") != "appraising.cif" {
+		t.Errorf("expected appraising.cif to be the only decision step, got %v", decisions)
+	}
+}
+
+func TestParseStepCatalogueValidation(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"steps":[{"name":"otp"},{"name":"otp"}]}`, `step "otp" is declared twice`},
+		{`{"steps":[{"name":"kyc.face"}]}`, `unknown namespace "kyc"`},
+		{`{"steps":[{"name":"otp","successors":["app_form.basic_info"]}]}`, `unknown successor "app_form.basic_info"`},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseStepCatalogue([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.data, tt.want, err)
+		}
+	}
+
+	catalogue, err := ParseStepCatalogue([]byte(`{"steps":[{"name":"done","namespace":"inform"},{"name":"cif.confirm","successors":["done"]}]}`))
+	if err != nil {
+		t.Fatalf("ParseStepCatalogue failed: %v", err)
+	}
+	if step, _ := catalogue.Lookup("cif.confirm"); step.Namespace != NamespaceCIF {
+		t.Errorf("expected the namespace to default to the name prefix, got %+v", step)
+	}
+}
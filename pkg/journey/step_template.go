@@ -0,0 +1,285 @@
+package journey
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/tsocial/ui-version-mapping/pkg/condition"
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// Config roles a step template can refer to
+const (
+	ConfigRoleSource = "source"
+	ConfigRoleTarget = "target"
+)
+
+//go:embed templates/default_step_templates.json
+var defaultStepTemplatesJSON []byte
+
+var (
+	defaultStepTemplates     *StepTemplateSet
+	defaultStepTemplatesOnce sync.Once
+)
+
+// StepTemplateSet holds the step templates used to build journeys between two configs.
+// Templates are matched in order against the journey flow type; Default is used when
+// none matches.
+type StepTemplateSet struct {
+	Version   int            `json:"version"`
+	Templates []FlowTemplate `json:"templates"`
+	Default   FlowTemplate   `json:"default"`
+}
+
+// FlowTemplate declares the steps of journeys whose flow type matches a pattern
+type FlowTemplate struct {
+	Name             string        `json:"name"`
+	FlowTypeContains []string      `json:"flow_type_contains,omitempty"`
+	Sections         []StepSection `json:"sections"`
+}
+
+// StepSection is a run of steps whose main UI version comes from the same config
+type StepSection struct {
+	UIVersionFrom string     `json:"ui_version_from"`
+	StepsFromFlow string     `json:"steps_from_flow,omitempty"`
+	Steps         []StepRule `json:"steps,omitempty"`
+}
+
+// StepRule declares a single step and its sub UI version rules
+type StepRule struct {
+	Name                     string                    `json:"name"`
+	RequireInFlow            string                    `json:"require_in_flow,omitempty"`
+	ExcludeFlowTypes         []string                  `json:"exclude_flow_types,omitempty"`
+	SubUIVersion             string                    `json:"sub_ui_version,omitempty"`
+	SubUIVersionByConditions []SubUIVersionByCondition `json:"sub_ui_version_by_conditions,omitempty"`
+	Variants                 []StepVariant             `json:"variants,omitempty"`
+}
+
+// StepVariant overrides the sub UI version rules of a step for matching flow types
+type StepVariant struct {
+	FlowTypeContains         string                    `json:"flow_type_contains"`
+	SubUIVersion             string                    `json:"sub_ui_version,omitempty"`
+	SubUIVersionByConditions []SubUIVersionByCondition `json:"sub_ui_version_by_conditions,omitempty"`
+}
+
+// DefaultStepTemplates returns the built-in step templates, which reproduce the
+// rejection, automated/semi and CIF journeys. The result is shared and must not be modified.
+func DefaultStepTemplates() *StepTemplateSet {
+	defaultStepTemplatesOnce.Do(func() {
+		templates, err := ParseStepTemplates(defaultStepTemplatesJSON)
+		if err != nil {
+			panic(fmt.Sprintf("invalid built-in step templates: %v", err))
+		}
+		defaultStepTemplates = templates
+	})
+	return defaultStepTemplates
+}
+
+// LoadStepTemplates reads and validates a step template file
+func LoadStepTemplates(filename string) (*StepTemplateSet, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read step templates %s: %w", filename, err)
+	}
+
+	templates, err := ParseStepTemplates(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid step templates %s: %w", filename, err)
+	}
+
+	return templates, nil
+}
+
+// ParseStepTemplates decodes and validates step templates from JSON
+func ParseStepTemplates(data []byte) (*StepTemplateSet, error) {
+	var templates StepTemplateSet
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal step templates: %w", err)
+	}
+
+	if err := templates.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &templates, nil
+}
+
+// Validate checks that every template is matchable and refers to known config roles
+func (s *StepTemplateSet) Validate() error {
+	for i, template := range s.Templates {
+		if template.Name == "" {
+			return fmt.Errorf("templates[%d]: name is required", i)
+		}
+		if len(template.FlowTypeContains) == 0 {
+			return fmt.Errorf("template %q: flow_type_contains is required", template.Name)
+		}
+		if err := template.validateSections(); err != nil {
+			return fmt.Errorf("template %q: %w", template.Name, err)
+		}
+	}
+
+	if err := s.Default.validateSections(); err != nil {
+		return fmt.Errorf("default template: %w", err)
+	}
+
+	return nil
+}
+
+func (t FlowTemplate) validateSections() error {
+	for i, section := range t.Sections {
+		if !isConfigRole(section.UIVersionFrom) {
+			return fmt.Errorf("sections[%d]: ui_version_from must be %q or %q, got %q",
+				i, ConfigRoleSource, ConfigRoleTarget, section.UIVersionFrom)
+		}
+		if section.StepsFromFlow != "" && !isConfigRole(section.StepsFromFlow) {
+			return fmt.Errorf("sections[%d]: steps_from_flow must be %q or %q, got %q",
+				i, ConfigRoleSource, ConfigRoleTarget, section.StepsFromFlow)
+		}
+		for j, step := range section.Steps {
+			if step.Name == "" {
+				return fmt.Errorf("sections[%d].steps[%d]: name is required", i, j)
+			}
+			if step.RequireInFlow != "" && !isConfigRole(step.RequireInFlow) {
+				return fmt.Errorf("sections[%d].steps[%d]: require_in_flow must be %q or %q, got %q",
+					i, j, ConfigRoleSource, ConfigRoleTarget, step.RequireInFlow)
+			}
+			conditions := append([]SubUIVersionByCondition(nil), step.SubUIVersionByConditions...)
+			for _, variant := range step.Variants {
+				conditions = append(conditions, variant.SubUIVersionByConditions...)
+			}
+			for _, c := range conditions {
+				if _, err := condition.Parse(c.Condition); err != nil {
+					return fmt.Errorf("sections[%d].steps[%d]: %w", i, j, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isConfigRole(role string) bool {
+	return role == ConfigRoleSource || role == ConfigRoleTarget
+}
+
+// Match returns the template for a flow type, falling back to the default template
+func (s *StepTemplateSet) Match(flowType string) FlowTemplate {
+	for _, template := range s.Templates {
+		for _, pattern := range template.FlowTypeContains {
+			if strings.Contains(flowType, pattern) {
+				return template
+			}
+		}
+	}
+	return s.Default
+}
+
+// StepNames returns the distinct step names declared by the templates, in declaration order
+func (s *StepTemplateSet) StepNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, template := range append(append([]FlowTemplate(nil), s.Templates...), s.Default) {
+		for _, section := range template.Sections {
+			for _, step := range section.Steps {
+				if !seen[step.Name] {
+					seen[step.Name] = true
+					names = append(names, step.Name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// GenerateSteps builds the steps of a journey from sourceConfig to targetConfig.
+// Sub UI versions declared in the ui_flow_settings of the config supplying a step's
+// main UI version take precedence over the template rules.
+func (s *StepTemplateSet) GenerateSteps(sourceConfig, targetConfig *config.LenderConfig, flowType string) []Step {
+	// For normal flow (self-loop), just use source config steps
+	if sourceConfig.ID == targetConfig.ID {
+		return GenerateConfigJourneySteps(sourceConfig)
+	}
+
+	pick := func(role string) *config.LenderConfig {
+		if role == ConfigRoleSource {
+			return sourceConfig
+		}
+		return targetConfig
+	}
+	// ui_flow_settings are decoded once per config, not per step
+	sourceSettings, targetSettings := StepSettingsOf(sourceConfig), StepSettingsOf(targetConfig)
+	settingsOf := func(role string) *config.UIFlowSettings {
+		if role == ConfigRoleSource {
+			return sourceSettings
+		}
+		return targetSettings
+	}
+
+	var steps []Step
+	for _, section := range s.Match(flowType).Sections {
+		uiVersion := pick(section.UIVersionFrom).UIVersion
+		versionSettings := settingsOf(section.UIVersionFrom)
+
+		if section.StepsFromFlow != "" {
+			for _, stepName := range pick(section.StepsFromFlow).UIFlow {
+				step := newStep(len(steps), stepName, uiVersion, "", nil)
+				ApplyStepSettings(&step, versionSettings)
+				steps = append(steps, step)
+			}
+		}
+
+		for _, rule := range section.Steps {
+			if rule.excludedFor(flowType) {
+				continue
+			}
+
+			// Step must sit at the current position of the referenced config's ui_flow
+			if rule.RequireInFlow != "" {
+				flow := pick(rule.RequireInFlow).UIFlow
+				if len(steps) >= len(flow) || flow[len(steps)] != rule.Name {
+					continue
+				}
+			}
+
+			subUIVersion, conditions := rule.subUIVersionFor(flowType)
+			step := newStep(len(steps), rule.Name, uiVersion, subUIVersion, conditions)
+			ApplyStepSettings(&step, versionSettings)
+			steps = append(steps, step)
+		}
+	}
+
+	return steps
+}
+
+func (r StepRule) excludedFor(flowType string) bool {
+	for _, pattern := range r.ExcludeFlowTypes {
+		if strings.Contains(flowType, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// subUIVersionFor applies the first variant matching flowType over the step defaults
+func (r StepRule) subUIVersionFor(flowType string) (string, []SubUIVersionByCondition) {
+	subUIVersion := r.SubUIVersion
+	conditions := r.SubUIVersionByConditions
+
+	for _, variant := range r.Variants {
+		if !strings.Contains(flowType, variant.FlowTypeContains) {
+			continue
+		}
+		if variant.SubUIVersion != "" {
+			subUIVersion = variant.SubUIVersion
+		}
+		if len(variant.SubUIVersionByConditions) > 0 {
+			conditions = variant.SubUIVersionByConditions
+		}
+		break
+	}
+
+	return subUIVersion, append([]SubUIVersionByCondition(nil), conditions...)
+}
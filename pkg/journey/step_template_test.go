@@ -0,0 +1,67 @@
+package journey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func TestDefaultStepTemplates(t *testing.T) {
+	source := &config.LenderConfig{ID: 1, UIVersion: "src", UIFlow: []string{"otp", "app_form.basic_info"}}
+	target := &config.LenderConfig{ID: 2, UIVersion: "tgt", UIFlow: []string{"cif.confirm"}}
+
+	tests := []struct {
+		flowType string
+		want     []string
+	}{
+		{"collect_to_rejection", []string{"otp@src", "app_form.basic_info@src", "ekyc.selfie.flash@tgt", "failure@tgt"}},
+		{"collect_to_cif", []string{"cif.confirm@tgt", "appraising.cif@tgt"}},
+		{"collect_to_cif_no_branch", []string{"cif.confirm@tgt"}},
+		{"collect_to_other", []string{"cif.confirm@tgt"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, step := range DefaultStepTemplates().GenerateSteps(source, target, tt.flowType) {
+			got = append(got, step.Name+"@"+step.MainUIVersion)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.flowType, got, tt.want)
+		}
+	}
+
+	semi := DefaultStepTemplates().GenerateSteps(source, target, "collect_to_semi")
+	if len(semi) != 20 || semi[14].Name != "esign.review" || semi[14].SubUIVersion != "v1.0-semi-nfc" {
+		t.Errorf("semi journey should switch esign.review to v1.0-semi-nfc, got %+v", semi[14])
+	}
+}
+
+func TestStepNames(t *testing.T) {
+	names := DefaultStepTemplates().StepNames()
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("duplicate step name %s", name)
+		}
+		seen[name] = true
+	}
+	for _, name := range []string{"otp", "ekyc.selfie.flash", "appraising.cif", "cif.confirm", "esign.review", "failure"} {
+		if !seen[name] {
+			t.Errorf("expected step %s in %v", name, names)
+		}
+	}
+}
+
+func TestParseStepTemplatesValidation(t *testing.T) {
+	_, err := ParseStepTemplates([]byte(`{"templates":[{"name":"x","flow_type_contains":["x"],"sections":[{"ui_version_from":"other"}]}]}`))
+	if err == nil || !strings.Contains(err.Error(), "ui_version_from") {
+		t.Errorf("expected ui_version_from validation error, got %v", err)
+	}
+
+	_, err = ParseStepTemplates([]byte(`{"templates":[{"name":"x","sections":[]}]}`))
+	if err == nil || !strings.Contains(err.Error(), "flow_type_contains") {
+		t.Errorf("expected flow_type_contains validation error, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package journey
+
+import (
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// StepUIVersionSettings holds the sub UI version settings of a step declared in ui_flow_settings
+type StepUIVersionSettings struct {
+	SubUIVersion             string
+	SubUIVersionByConditions []SubUIVersionByCondition
+}
+
+// StepSettingsOf decodes the ui_flow_settings of cfg once, for use with ResolveStepSettings
+// and ApplyStepSettings. It returns nil when cfg has no usable settings; decoding issues are
+// reported by the ui-flow-settings lint rule, resolution uses whatever decoded cleanly.
+func StepSettingsOf(cfg *config.LenderConfig) *config.UIFlowSettings {
+	if cfg == nil || cfg.UIFlowSettings == nil {
+		return nil
+	}
+	typed, _, err := cfg.TypedUIFlowSettings()
+	if err != nil {
+		return nil
+	}
+	return typed
+}
+
+// ResolveStepSettings reads the settings of stepName from decoded ui_flow_settings.
+// Steps are keyed by name, e.g.
+//
+//	"ui_flow_settings": {
+//	  "esign.review": {
+//	    "sub_ui_version": "v1.0-auto-nfc",
+//	    "sub_ui_version_by_conditions": [
+//	      {"condition": "telco_code=viettel", "sub_ui_version": "v1.1-auto-nfc"}
+//	    ]
+//	  }
+//	}
+//
+// The boolean result reports whether the step has any sub UI version settings.
+func ResolveStepSettings(typed *config.UIFlowSettings, stepName string) (StepUIVersionSettings, bool) {
+	var settings StepUIVersionSettings
+	stepSettings, ok := typed.Step(stepName)
+	if !ok {
+		return settings, false
+	}
+
+	settings.SubUIVersion = stepSettings.SubUIVersion
+	for _, condition := range stepSettings.SubUIVersionByConditions {
+		if condition.Condition == "" || condition.SubUIVersion == "" {
+			continue
+		}
+		settings.SubUIVersionByConditions = append(settings.SubUIVersionByConditions, SubUIVersionByCondition{
+			Condition:    condition.Condition,
+			SubUIVersion: condition.SubUIVersion,
+		})
+	}
+
+	found := settings.SubUIVersion != "" || len(settings.SubUIVersionByConditions) > 0
+	return settings, found
+}
+
+// ApplyStepSettings overrides the step's sub UI versions with the decoded ui_flow_settings
+// of a config; values absent from the settings keep the template defaults
+func ApplyStepSettings(step *Step, typed *config.UIFlowSettings) {
+	settings, ok := ResolveStepSettings(typed, step.Name)
+	if !ok {
+		return
+	}
+
+	if settings.SubUIVersion != "" {
+		step.SubUIVersion = settings.SubUIVersion
+	}
+	if len(settings.SubUIVersionByConditions) > 0 {
+		step.SubUIVersionByConditions = settings.SubUIVersionByConditions
+	}
+}
+
+// GenerateConfigJourneySteps creates the steps of a config's own ui_flow with sub UI
+// versions resolved from its ui_flow_settings
+func GenerateConfigJourneySteps(cfg *config.LenderConfig) []Step {
+	steps := GenerateStandardJourneySteps(cfg.UIFlow, cfg.UIVersion)
+	settings := StepSettingsOf(cfg)
+	for i := range steps {
+		ApplyStepSettings(&steps[i], settings)
+	}
+	return steps
+}
@@ -0,0 +1,74 @@
+package journey
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func TestUIFlowSettingsOverrideTemplate(t *testing.T) {
+	var target config.LenderConfig
+	err := json.Unmarshal([]byte(`{
+		"id": 2,
+		"ui_version": "tgt",
+		"ui_flow_settings": {
+			"esign.review": {"sub_ui_version": "v2.0-auto-nfc"},
+			"inform.success": {
+				"sub_ui_version_by_conditions": [
+					{"condition": "lead_source=paid", "sub_ui_version": "v2.0-paid"}
+				]
+			}
+		}
+	}`), &target)
+	if err != nil {
+		t.Fatalf("failed to decode config: %v", err)
+	}
+	source := &config.LenderConfig{ID: 1, UIVersion: "src"}
+
+	steps := DefaultStepTemplates().GenerateSteps(source, &target, "collect_to_auto_pcb")
+	byName := make(map[string]Step)
+	for _, step := range steps {
+		byName[step.Name] = step
+	}
+
+	if got := byName["esign.review"].SubUIVersion; got != "v2.0-auto-nfc" {
+		t.Errorf("esign.review sub UI version = %q, want v2.0-auto-nfc", got)
+	}
+
+	conditions := byName["inform.success"].SubUIVersionByConditions
+	if len(conditions) != 1 || conditions[0].SubUIVersion != "v2.0-paid" {
+		t.Errorf("inform.success conditions = %+v, want the config override", conditions)
+	}
+
+	// Steps without settings keep the template defaults
+	if got := byName["esign.intro"].SubUIVersion; got != "v1.0-c1" {
+		t.Errorf("esign.intro sub UI version = %q, want template default v1.0-c1", got)
+	}
+}
+
+func TestConfigStepsKeepValidConditions(t *testing.T) {
+	var cfg config.LenderConfig
+	err := json.Unmarshal([]byte(`{
+		"id": 1,
+		"ui_version": "v1",
+		"ui_flow": ["otp"],
+		"ui_flow_settings": {
+			"otp": {
+				"sub_ui_version_by_conditions": [
+					{"condition": "telco_code=viettel", "sub_ui_version": "v1.1"},
+					{"condition": "telco_code=mobifone", "sub_ui_version": 2}
+				]
+			}
+		}
+	}`), &cfg)
+	if err != nil {
+		t.Fatalf("failed to decode config: %v", err)
+	}
+
+	steps := GenerateConfigJourneySteps(&cfg)
+	conditions := steps[0].SubUIVersionByConditions
+	if len(conditions) != 1 || conditions[0].SubUIVersion != "v1.1" {
+		t.Errorf("expected the valid condition despite the malformed one, got %+v", conditions)
+	}
+}
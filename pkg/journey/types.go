@@ -19,6 +19,8 @@ type Step struct {
 	MainUIVersion            string                    `json:"main_ui_version"`
 	SubUIVersion             string                    `json:"sub_ui_version"`
 	SubUIVersionByConditions []SubUIVersionByCondition `json:"sub_ui_version_by_conditions"`
+	// Decision marks decision steps of the step catalogue; it is not part of the template JSON
+	Decision bool `json:"-"`
 }
 
 // SubUIVersionByCondition represents conditional UI version logic
@@ -0,0 +1,132 @@
+// Package lint checks lender configs against a catalogue of rules before they ship.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+// Severity of a lint finding
+type Severity string
+
+// Finding severities; errors make the lint fail
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule is a check applied to every config. Check returns one message per problem found.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+	Check       func(cfg *config.LenderConfig, opts *Options) []string
+}
+
+// Finding is a problem a rule found in a config
+type Finding struct {
+	RuleID   string   `json:"rule_id"`
+	Severity Severity `json:"severity"`
+	ConfigID int      `json:"config_id"`
+	File     string   `json:"file,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// String renders the finding as "file: [severity] rule: message"
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: [%s] %s: %s", f.location(), f.Severity, f.RuleID, f.Message)
+}
+
+func (f Finding) location() string {
+	if f.File != "" {
+		return f.File
+	}
+	return fmt.Sprintf("config %d", f.ConfigID)
+}
+
+// Report is the result of linting a set of configs
+type Report struct {
+	Configs  int       `json:"configs"`
+	Rules    []string  `json:"rules"`
+	Findings []Finding `json:"findings"`
+}
+
+// HasErrors reports whether any finding has error severity
+func (r *Report) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+// Count returns the number of findings with a severity
+func (r *Report) Count(severity Severity) int {
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// String renders the report for terminal output
+func (r *Report) String() string {
+	var out strings.Builder
+	for _, finding := range r.Findings {
+		out.WriteString(finding.String() + "\n")
+	}
+	out.WriteString(fmt.Sprintf("Linted %d configs with %d rules: %d errors, %d warnings\n",
+		r.Configs, len(r.Rules), r.Count(SeverityError), r.Count(SeverityWarning)))
+	return out.String()
+}
+
+// Linter runs rules over configs, skipping the rules its policy disables
+type Linter struct {
+	Rules   []Rule
+	Options Options
+	Policy  Policy
+}
+
+// NewLinter creates a linter with the built-in rule catalogue and default options
+func NewLinter(policy Policy) *Linter {
+	return &Linter{
+		Rules:   Catalogue(),
+		Options: DefaultOptions(),
+		Policy:  policy,
+	}
+}
+
+// Lint checks every config and returns the findings sorted by file, in catalogue order per file
+func (l *Linter) Lint(configs []*config.LenderConfig) *Report {
+	report := &Report{
+		Configs:  len(configs),
+		Findings: []Finding{},
+	}
+	for _, rule := range l.Rules {
+		report.Rules = append(report.Rules, rule.ID)
+	}
+
+	for _, cfg := range configs {
+		for _, rule := range l.Rules {
+			if l.Policy.Disabled(rule.ID, cfg.SourcePath) {
+				continue
+			}
+			for _, message := range rule.Check(cfg, &l.Options) {
+				report.Findings = append(report.Findings, Finding{
+					RuleID:   rule.ID,
+					Severity: rule.Severity,
+					ConfigID: cfg.ID,
+					File:     cfg.SourcePath,
+					Message:  message,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].location() < report.Findings[j].location()
+	})
+
+	return report
+}
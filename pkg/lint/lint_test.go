@@ -0,0 +1,75 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func TestLintPolicy(t *testing.T) {
+	evo := validConfig()
+	evo.SourcePath = "vendor/configs/evo/9054_organic.json"
+	evo.Weight = 150
+
+	win := validConfig()
+	win.ID = 9100
+	win.SourcePath = "v1.2.3:lender_configs/win/legacy/9100.json"
+	win.Weight = 150
+	win.Tags = nil
+
+	policy := Policy{
+		Folders: map[string]FolderPolicy{
+			"win/legacy": {Disable: []string{RuleWeightRange, RuleRequiredTags}},
+		},
+	}
+	report := NewLinter(policy).Lint([]*config.LenderConfig{win, evo})
+
+	if len(report.Findings) != 1 {
+		t.Fatalf("expected only the evo weight finding, got %v", report.Findings)
+	}
+	if report.Findings[0].ConfigID != 9054 || report.Findings[0].RuleID != RuleWeightRange {
+		t.Errorf("unexpected finding %s", report.Findings[0])
+	}
+	if !report.HasErrors() || report.Count(SeverityWarning) != 0 {
+		t.Errorf("expected 1 error and no warnings, got %s", report)
+	}
+
+	// Disabling globally silences evo too
+	policy.Disable = []string{RuleWeightRange}
+	if report := NewLinter(policy).Lint([]*config.LenderConfig{win, evo}); len(report.Findings) != 0 {
+		t.Errorf("expected no findings, got %v", report.Findings)
+	}
+
+	// A folder only matches whole path components
+	if (Policy{Folders: map[string]FolderPolicy{"ev": {Disable: []string{RuleWeightRange}}}}).Disabled(RuleWeightRange, evo.SourcePath) {
+		t.Error("expected folder ev not to match evo")
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		t.Helper()
+		file := filepath.Join(dir, "lint.json")
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	policy, err := LoadPolicy(write(`{"disable": ["ui-flow-settings"], "folders": {"win": {"disable": ["required-tags"]}}}`))
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	if !policy.Disabled(RuleRequiredTags, "configs/win/9100.json") || policy.Disabled(RuleRequiredTags, "configs/evo/9054.json") {
+		t.Errorf("unexpected folder policy %+v", policy)
+	}
+
+	_, err = LoadPolicy(write(`{"folders": {"win": {"disable": ["no-such-rule"]}}}`))
+	if err == nil || !strings.Contains(err.Error(), `unknown rule "no-such-rule"`) {
+		t.Errorf("expected an unknown rule error, got %v", err)
+	}
+}
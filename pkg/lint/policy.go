@@ -0,0 +1,135 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Policy disables rules everywhere or for the configs of some folders, e.g.
+//
+//	{
+//	  "disable": ["ui-flow-settings"],
+//	  "folders": {
+//	    "win": {"disable": ["required-tags"]},
+//	    "evo/legacy": {"disable": ["unknown-step", "weight-range"]}
+//	  }
+//	}
+//
+// A folder matches the configs whose directory contains its path components in order,
+// so "evo" applies to vendor/configs/evo/9054.json and to lender_configs/evo/sub/9055.json.
+type Policy struct {
+	Disable []string                `json:"disable,omitempty"`
+	Folders map[string]FolderPolicy `json:"folders,omitempty"`
+}
+
+// FolderPolicy disables rules for the configs of a folder
+type FolderPolicy struct {
+	Disable []string `json:"disable"`
+}
+
+// LoadPolicy reads a policy file and checks that it only refers to rules of the catalogue
+func LoadPolicy(filename string) (Policy, error) {
+	var policy Policy
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return policy, fmt.Errorf("failed to read lint policy %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return policy, fmt.Errorf("failed to unmarshal lint policy %s: %w", filename, err)
+	}
+	if err := policy.Validate(Catalogue()); err != nil {
+		return policy, fmt.Errorf("invalid lint policy %s: %w", filename, err)
+	}
+
+	return policy, nil
+}
+
+// Validate checks that the policy only disables rules that exist
+func (p Policy) Validate(rules []Rule) error {
+	known := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		known[rule.ID] = true
+	}
+
+	for _, id := range p.Disable {
+		if !known[id] {
+			return fmt.Errorf("unknown rule %q", id)
+		}
+	}
+	for folder, folderPolicy := range p.Folders {
+		for _, id := range folderPolicy.Disable {
+			if !known[id] {
+				return fmt.Errorf("folder %s: unknown rule %q", folder, id)
+			}
+		}
+	}
+	return nil
+}
+
+// Disabled reports whether a rule is disabled for the config at sourcePath
+func (p Policy) Disabled(ruleID, sourcePath string) bool {
+	if contains(p.Disable, ruleID) {
+		return true
+	}
+
+	dirs := splitPath(path.Dir(toSlash(sourcePath)))
+	for folder, folderPolicy := range p.Folders {
+		if contains(folderPolicy.Disable, ruleID) && containsRun(dirs, splitPath(toSlash(folder))) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRun reports whether run appears as consecutive elements of dirs
+func containsRun(dirs, run []string) bool {
+	if len(run) == 0 {
+		return false
+	}
+	for i := 0; i+len(run) <= len(dirs); i++ {
+		match := true
+		for j := range run {
+			if dirs[i+j] != run[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func splitPath(p string) []string {
+	var parts []string
+	for _, part := range strings.Split(p, "/") {
+		if part != "" && part != "." {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// toSlash converts a file path to slashes and drops the "ref:" prefix of git source paths
+func toSlash(p string) string {
+	p = filepath.ToSlash(p)
+	if i := strings.Index(p, ":"); i >= 0 && !strings.Contains(p[:i], "/") {
+		p = p[i+1:]
+	}
+	return p
+}
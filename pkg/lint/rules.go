@@ -0,0 +1,232 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
+)
+
+// Rule IDs of the built-in catalogue
+const (
+	RuleUIVersionFormat     = "ui-version-format"
+	RuleUIFlowEmpty         = "ui-flow-empty"
+	RuleUIFlowDuplicateStep = "ui-flow-duplicate-step"
+	RuleUnknownStep         = "unknown-step"
+	RuleRequiredTags        = "required-tags"
+	RuleWeightRange         = "weight-range"
+	RuleDecisionEngineTree  = "decision-engine-tree-uuid"
+	RuleUIFlowSettings      = "ui-flow-settings"
+	RuleStepSuccessor       = "step-successor"
+)
+
+// UIVersionPattern is the ui_version format of lender configs, e.g. "v9.1.5.0"
+var UIVersionPattern = regexp.MustCompile(`^v\d+\.\d+\.\d+\.\d+$`)
+
+// Options parameterise the built-in rules
+type Options struct {
+	// UIVersionPattern is the format ui_version must match
+	UIVersionPattern *regexp.Regexp
+	// Steps is the catalogue of the steps ui_flow may use and of their allowed successors
+	Steps *journey.StepCatalogue
+	// RequiredTags must be present on every config. flow_type is also satisfied by esign_flow_type.
+	RequiredTags []string
+	// MinWeight and MaxWeight bound the weight of a config, inclusive
+	MinWeight int
+	MaxWeight int
+}
+
+// DefaultOptions returns the options of the built-in rules; known steps are the steps of
+// the built-in step catalogue
+func DefaultOptions() Options {
+	return Options{
+		UIVersionPattern: UIVersionPattern,
+		Steps:            journey.DefaultStepCatalogue(),
+		RequiredTags:     []string{"product_code", "lead_source", "flow_type"},
+		MinWeight:        0,
+		MaxWeight:        100,
+	}
+}
+
+// Catalogue returns the built-in rules
+func Catalogue() []Rule {
+	return []Rule{
+		{
+			ID:          RuleUIVersionFormat,
+			Severity:    SeverityError,
+			Description: "ui_version follows the vMAJOR.MINOR.PATCH.BUILD format",
+			Check:       checkUIVersionFormat,
+		},
+		{
+			ID:          RuleUIFlowEmpty,
+			Severity:    SeverityError,
+			Description: "ui_flow has at least one step",
+			Check:       checkUIFlowEmpty,
+		},
+		{
+			ID:          RuleUIFlowDuplicateStep,
+			Severity:    SeverityError,
+			Description: "ui_flow lists every step once",
+			Check:       checkUIFlowDuplicateStep,
+		},
+		{
+			ID:          RuleUnknownStep,
+			Severity:    SeverityError,
+			Description: "ui_flow only uses known step names",
+			Check:       checkUnknownStep,
+		},
+		{
+			ID:          RuleRequiredTags,
+			Severity:    SeverityError,
+			Description: "product_code, lead_source and flow_type tags are present",
+			Check:       checkRequiredTags,
+		},
+		{
+			ID:          RuleWeightRange,
+			Severity:    SeverityError,
+			Description: "weight is within the allowed range (0-100)",
+			Check:       checkWeightRange,
+		},
+		{
+			ID:          RuleDecisionEngineTree,
+			Severity:    SeverityError,
+			Description: "decision_engines entries have a tree_uuid",
+			Check:       checkDecisionEngineTree,
+		},
+		{
+			ID:          RuleUIFlowSettings,
+			Severity:    SeverityWarning,
+			Description: "ui_flow_settings decode without issues",
+			Check:       checkUIFlowSettings,
+		},
+		{
+			ID:          RuleStepSuccessor,
+			Severity:    SeverityWarning,
+			Description: "consecutive ui_flow steps are allowed successors in the step catalogue",
+			Check:       checkStepSuccessor,
+		},
+	}
+}
+
+func checkUIVersionFormat(cfg *config.LenderConfig, opts *Options) []string {
+	if opts.UIVersionPattern == nil || opts.UIVersionPattern.MatchString(cfg.UIVersion) {
+		return nil
+	}
+	if cfg.UIVersion == "" {
+		return []string{"ui_version is missing"}
+	}
+	return []string{fmt.Sprintf("ui_version %q does not match %s", cfg.UIVersion, opts.UIVersionPattern)}
+}
+
+func checkUIFlowEmpty(cfg *config.LenderConfig, opts *Options) []string {
+	if len(cfg.UIFlow) == 0 {
+		return []string{"ui_flow is empty"}
+	}
+	return nil
+}
+
+func checkUIFlowDuplicateStep(cfg *config.LenderConfig, opts *Options) []string {
+	var messages []string
+	first := make(map[string]int)
+	for i, step := range cfg.UIFlow {
+		if at, ok := first[step]; ok {
+			messages = append(messages, fmt.Sprintf("step %s at position %d repeats position %d", step, i+1, at+1))
+			continue
+		}
+		first[step] = i
+	}
+	return messages
+}
+
+func checkUnknownStep(cfg *config.LenderConfig, opts *Options) []string {
+	if opts.Steps == nil {
+		return nil
+	}
+
+	var messages []string
+	for i, step := range cfg.UIFlow {
+		if _, ok := opts.Steps.Lookup(step); !ok {
+			messages = append(messages, fmt.Sprintf("unknown step %s at position %d", step, i+1))
+		}
+	}
+	return messages
+}
+
+// checkStepSuccessor skips unknown and repeated steps, which unknown-step and
+// ui-flow-duplicate-step already report
+func checkStepSuccessor(cfg *config.LenderConfig, opts *Options) []string {
+	if opts.Steps == nil {
+		return nil
+	}
+
+	var messages []string
+	seen := make(map[string]bool)
+	for i, step := range cfg.UIFlow {
+		_, known := opts.Steps.Lookup(step)
+		if i > 0 && known && !seen[step] {
+			previous := cfg.UIFlow[i-1]
+			if !opts.Steps.AllowsTransition(previous, step) {
+				messages = append(messages, fmt.Sprintf("step %s at position %d may not follow %s", step, i+1, previous))
+			}
+		}
+		seen[step] = true
+	}
+	return messages
+}
+
+func checkRequiredTags(cfg *config.LenderConfig, opts *Options) []string {
+	present := make(map[string]bool)
+	for _, tag := range cfg.Tags {
+		if tag.Value != "" {
+			present[tag.Name] = true
+		}
+	}
+	if present["esign_flow_type"] {
+		present["flow_type"] = true
+	}
+
+	var missing []string
+	for _, name := range opts.RequiredTags {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("missing tags: %s", strings.Join(missing, ", "))}
+}
+
+func checkWeightRange(cfg *config.LenderConfig, opts *Options) []string {
+	if cfg.Weight < opts.MinWeight || cfg.Weight > opts.MaxWeight {
+		return []string{fmt.Sprintf("weight %d is outside %d-%d", cfg.Weight, opts.MinWeight, opts.MaxWeight)}
+	}
+	return nil
+}
+
+func checkDecisionEngineTree(cfg *config.LenderConfig, opts *Options) []string {
+	names := make([]string, 0, len(cfg.DecisionEngines))
+	for name := range cfg.DecisionEngines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var messages []string
+	for _, name := range names {
+		if strings.TrimSpace(cfg.DecisionEngines[name].TreeUUID) == "" {
+			messages = append(messages, fmt.Sprintf("decision_engines.%s has no tree_uuid", name))
+		}
+	}
+	return messages
+}
+
+func checkUIFlowSettings(cfg *config.LenderConfig, opts *Options) []string {
+	var messages []string
+	for _, issue := range config.ValidateUIFlowSettings([]*config.LenderConfig{cfg}) {
+		messages = append(messages, fmt.Sprintf("%s: %s", issue.Path, issue.Message))
+	}
+	return messages
+}
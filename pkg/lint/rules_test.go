@@ -0,0 +1,79 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsocial/ui-version-mapping/pkg/config"
+)
+
+func validConfig() *config.LenderConfig {
+	return &config.LenderConfig{
+		ID:        9054,
+		Name:      "collect",
+		UIVersion: "v9.1.5.0",
+		UIFlow:    []string{"otp", "app_form.basic_info", "ekyc.selfie.active", "inform.success", "esign.review"},
+		Tags: []config.Tag{
+			{Name: "product_code", Value: "evo"},
+			{Name: "lead_source", Value: "organic"},
+			{Name: "flow_type", Value: "automated"},
+		},
+		Weight:          100,
+		DecisionEngines: map[string]config.DecisionEngine{"appraising": {TreeUUID: "tree-1"}},
+	}
+}
+
+func TestCatalogueAcceptsValidConfig(t *testing.T) {
+	report := NewLinter(Policy{}).Lint([]*config.LenderConfig{validConfig()})
+	if len(report.Findings) != 0 {
+		t.Fatalf("expected no findings, got %v", report.Findings)
+	}
+}
+
+func TestCatalogueRules(t *testing.T) {
+	tests := []struct {
+		rule    string
+		mutate  func(cfg *config.LenderConfig)
+		message string
+	}{
+		{RuleUIVersionFormat, func(cfg *config.LenderConfig) { cfg.UIVersion = "9.1.5" }, `ui_version "9.1.5" does not match`},
+		{RuleUIVersionFormat, func(cfg *config.LenderConfig) { cfg.UIVersion = "" }, "ui_version is missing"},
+		{RuleUIFlowEmpty, func(cfg *config.LenderConfig) { cfg.UIFlow = nil }, "ui_flow is empty"},
+		{RuleUIFlowDuplicateStep, func(cfg *config.LenderConfig) { cfg.UIFlow = append(cfg.UIFlow, "otp") }, "step otp at position 6 repeats position 1"},
+		{RuleUnknownStep, func(cfg *config.LenderConfig) { cfg.UIFlow[1] = "app_form.unknown" }, "unknown step app_form.unknown at position 2"},
+		{RuleRequiredTags, func(cfg *config.LenderConfig) { cfg.Tags = cfg.Tags[1:2] }, "missing tags: product_code, flow_type"},
+		{RuleWeightRange, func(cfg *config.LenderConfig) { cfg.Weight = 120 }, "weight 120 is outside 0-100"},
+		{RuleDecisionEngineTree, func(cfg *config.LenderConfig) {
+			cfg.DecisionEngines["credit"] = config.DecisionEngine{TreeUUID: " "}
+		}, "decision_engines.credit has no tree_uuid"},
+		{RuleUIFlowSettings, func(cfg *config.LenderConfig) {
+			cfg.UIFlowSettings = map[string]interface{}{"otp": "v1.0"}
+		}, "$.otp"},
+		{RuleStepSuccessor, func(cfg *config.LenderConfig) { cfg.UIFlow[2] = "ekyc.selfie.flash" }, "step inform.success at position 4 may not follow ekyc.selfie.flash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			cfg := validConfig()
+			tt.mutate(cfg)
+
+			report := NewLinter(Policy{}).Lint([]*config.LenderConfig{cfg})
+			if len(report.Findings) != 1 {
+				t.Fatalf("expected 1 finding, got %v", report.Findings)
+			}
+			finding := report.Findings[0]
+			if finding.RuleID != tt.rule || !strings.Contains(finding.Message, tt.message) {
+				t.Errorf("expected %s finding containing %q, got %s", tt.rule, tt.message, finding)
+			}
+		})
+	}
+}
+
+func TestRequiredTagsAcceptsEsignFlowType(t *testing.T) {
+	cfg := validConfig()
+	cfg.Tags[2] = config.Tag{Name: "esign_flow_type", Value: "semi"}
+
+	if messages := checkRequiredTags(cfg, &Options{RequiredTags: []string{"flow_type"}}); len(messages) != 0 {
+		t.Errorf("expected esign_flow_type to satisfy flow_type, got %v", messages)
+	}
+}
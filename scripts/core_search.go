@@ -8,6 +8,9 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	pkgconfig "github.com/tsocial/ui-version-mapping/pkg/config"
+	"github.com/tsocial/ui-version-mapping/pkg/journey"
 )
 
 // Global path constants - configurable paths for lender configs
@@ -110,22 +113,44 @@ func ListFilesContainingKeyword(path string, keyword int) [][]string {
 	return matchingFiles
 }
 
-// SearchLenderConfigID finds the file name and path for a given lender config ID
+// SearchLenderConfigID finds the file name and path for a given lender config ID.
+// It returns empty strings when the config is missing or declared by several files.
 func SearchLenderConfigID(lenderConfigID int) (string, string) {
-	listFiles := ListFilesContainingKeyword(DJLenderConfigsPath, lenderConfigID)
-	if len(listFiles) == 0 {
-		fmt.Printf("Warning: No files found for Lender Config ID %d.\n", lenderConfigID)
+	name, path, err := LookupLenderConfigID(lenderConfigID)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
 		return "", ""
 	}
-	if len(listFiles) > 1 {
-		fmt.Printf("Warning: Multiple files found for Lender Config ID %d. Using the first match.\n", lenderConfigID)
-	}
-	name := listFiles[0][0]
-	path := listFiles[0][1]
 
 	return name, path
 }
 
+// LookupLenderConfigID finds the file name and path of the config whose id is lenderConfigID.
+// Files whose name merely contains the ID (905 in 9054_organic.json) are ruled out by reading
+// them; several matching files are a *pkgconfig.AmbiguousConfigError.
+func LookupLenderConfigID(lenderConfigID int) (string, string, error) {
+	var matches [][]string
+	for _, file := range ListFilesContainingKeyword(DJLenderConfigsPath, lenderConfigID) {
+		config, err := ReadLenderConfig(filepath.Join(file[1], file[0]))
+		if err == nil && config != nil && config.ID == lenderConfigID {
+			matches = append(matches, file)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", "", fmt.Errorf("no files found for Lender Config ID %d", lenderConfigID)
+	case 1:
+		return matches[0][0], matches[0][1], nil
+	}
+
+	var candidates []string
+	for _, file := range matches {
+		candidates = append(candidates, filepath.Join(file[1], file[0]))
+	}
+	return "", "", &pkgconfig.AmbiguousConfigError{ConfigID: lenderConfigID, Candidates: candidates}
+}
+
 // WriteSearchResultToJSON writes search results to a JSON file
 func WriteSearchResultToJSON(result SearchResult, filename string) error {
 	if err := CheckFile(filename); err != nil {
@@ -1006,13 +1031,15 @@ func GenerateFullJourneySteps(sourceConfig, targetConfig *LenderConfig, flowType
 			"ekyc.id_card", "ekyc.confirm", "appraising.third_approval", "appraising.fourth_approval",
 		}
 
+		// Decode the source settings once for all steps
+		sourceSettings := sourceStepSettings(sourceConfig)
 		for _, stepName := range initialSteps {
 			steps = append(steps, Step{
 				ID:                       stepID,
 				Name:                     stepName,
 				MainUIVersion:            sourceConfig.UIVersion,
-				SubUIVersion:             getSubUIVersionForStep(stepName, sourceConfig),
-				SubUIVersionByConditions: getSubUIVersionConditions(stepName, sourceConfig),
+				SubUIVersion:             getSubUIVersionForStep(stepName, sourceSettings),
+				SubUIVersionByConditions: getSubUIVersionConditions(stepName, sourceSettings),
 			})
 			stepID++
 		}
@@ -1098,17 +1125,35 @@ func GenerateFullJourneySteps(sourceConfig, targetConfig *LenderConfig, flowType
 }
 
 // Helper functions for step generation
-func getSubUIVersionForStep(stepName string, config *LenderConfig) string {
-	// Add logic to determine sub UI version based on step and config
+
+// sourceStepSettings decodes the config's ui_flow_settings; nil when it has none or they are invalid
+func sourceStepSettings(config *LenderConfig) *pkgconfig.UIFlowSettings {
+	return journey.StepSettingsOf(&pkgconfig.LenderConfig{UIFlowSettings: config.UIFlowSettings})
+}
+
+func getSubUIVersionForStep(stepName string, typed *pkgconfig.UIFlowSettings) string {
+	if settings, ok := journey.ResolveStepSettings(typed, stepName); ok && settings.SubUIVersion != "" {
+		return settings.SubUIVersion
+	}
 	if stepName == "app_form.personal_info" {
 		return "v1.0-c1"
 	}
 	return ""
 }
 
-func getSubUIVersionConditions(stepName string, config *LenderConfig) []SubUIVersionByCondition {
-	// Add logic to determine sub UI version conditions
-	return []SubUIVersionByCondition{}
+func getSubUIVersionConditions(stepName string, typed *pkgconfig.UIFlowSettings) []SubUIVersionByCondition {
+	conditions := []SubUIVersionByCondition{}
+	settings, ok := journey.ResolveStepSettings(typed, stepName)
+	if !ok {
+		return conditions
+	}
+	for _, condition := range settings.SubUIVersionByConditions {
+		conditions = append(conditions, SubUIVersionByCondition{
+			Condition:    condition.Condition,
+			SubUIVersion: condition.SubUIVersion,
+		})
+	}
+	return conditions
 }
 
 // GenerateJourneyTemplate creates a complete journey template for a lender config